@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 
 	"github.com/logrusorgru/aurora"
 	"golang.org/x/term"
@@ -15,7 +16,7 @@ type Ansi struct {
 
 // New returns new ansi instance
 func New(useColors bool, w io.Writer) *Ansi {
-	if !isTerminal(w) {
+	if !IsTerminal(w) {
 		useColors = false
 	}
 
@@ -39,6 +40,16 @@ func (a *Ansi) Red(message string) string {
 	return a.au.Sprintf(a.au.Red(message))
 }
 
+// Yellow colors given message yellow
+func (a *Ansi) Yellow(message string) string {
+	return a.au.Sprintf(a.au.Yellow(message))
+}
+
+// Reverse swaps foreground and background color of given message (e.g. for selections)
+func (a *Ansi) Reverse(message string) string {
+	return a.au.Sprintf(a.au.Reverse(message))
+}
+
 // ColorizeHTTPStatusCode colorizes given HTTP status code in green, yellow and red
 func (a *Ansi) ColorizeHTTPStatusCode(httpStatusCode int) aurora.Value {
 	status := fmt.Sprintf(" %d ", httpStatusCode)
@@ -55,8 +66,9 @@ func (a *Ansi) ColorizeHTTPStatusCode(httpStatusCode int) aurora.Value {
 	}
 }
 
-func isTerminal(w io.Writer) bool {
-	switch v := w.(type) {
+// IsTerminal returns true if given writer (or reader) is a terminal
+func IsTerminal(file any) bool {
+	switch v := file.(type) {
 	case *os.File:
 		return term.IsTerminal(int(v.Fd()))
 
@@ -64,3 +76,10 @@ func isTerminal(w io.Writer) bool {
 		return false
 	}
 }
+
+var escapeSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// Strip removes all escape sequences (e.g. colors) from given message
+func Strip(message string) string {
+	return escapeSequence.ReplaceAllString(message, "")
+}
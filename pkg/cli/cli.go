@@ -4,16 +4,28 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/codegen"
+	"github.com/corbado/cli/pkg/diff"
+	"github.com/corbado/cli/pkg/export"
+	"github.com/corbado/cli/pkg/gentest"
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/scaffold"
+	"github.com/corbado/cli/pkg/tunnel"
 )
 
 type CLI struct {
-	out     io.Writer
+	in      io.Reader
+	out     *syncWriter
 	rootCmd *cobra.Command
 }
 
@@ -26,7 +38,8 @@ func New(out io.Writer) *CLI {
 	}
 
 	c := &CLI{
-		out: out,
+		in:  os.Stdin,
+		out: &syncWriter{out: out},
 	}
 
 	c.defineCommands()
@@ -34,6 +47,21 @@ func New(out io.Writer) *CLI {
 	return c
 }
 
+// SetIn sets the input interactive modes read from (os.Stdin by default)
+func (c *CLI) SetIn(in io.Reader) {
+	c.in = in
+}
+
+// withOutput returns a copy of the CLI printing to given writer (e.g. status
+// output to stderr if stdout is meant for scripts)
+func (c *CLI) withOutput(out io.Writer) *CLI {
+	return &CLI{
+		in:      c.in,
+		out:     &syncWriter{out: out},
+		rootCmd: c.rootCmd,
+	}
+}
+
 // Execute executes command
 func (c *CLI) Execute() error {
 	if err := c.rootCmd.Execute(); err != nil {
@@ -81,12 +109,55 @@ func (c *CLI) defineCommands() {
 	logoutCmd.PersistentFlags().String("credentialFile", "$HOME/.corbado", "Credentials file location")
 
 	// Subscribe
+	subscribeCmd := c.newSubscribeCommand()
+
+	// Wait for
+	waitForCmd := c.newWaitForCommand()
+
+	// Test
+	testCmd := c.newTestCommand()
+
+	// Load
+	loadCmd := c.newLoadCommand()
+
+	// History
+	historyCmd := c.newHistoryCommand()
+
+	// Diff
+	diffCmd := c.newDiffCommand()
+
+	// Codegen
+	codegenCmd := c.newCodegenCommand()
+
+	// Init
+	initCmd := c.newInitCommand()
+
+	// Root
+	c.rootCmd = &cobra.Command{Use: cliName}
+	c.rootCmd.PersistentFlags().Bool("colors", true, "Defines if colors are used on output")
+	c.rootCmd.AddCommand(loginCmd, logoutCmd, subscribeCmd, waitForCmd, testCmd, loadCmd, historyCmd, diffCmd, codegenCmd, initCmd)
+}
+
+func (c *CLI) newSubscribeCommand() *cobra.Command {
 	subscribeCmd := &cobra.Command{
 		Use:     "subscribe <localAddress>",
-		Example: cliName + " subscribe http://localhost:8000",
+		Example: cliName + " subscribe http://localhost:8000\n" + cliName + " subscribe --echo",
 		Short:   "Subscribes to webhook requests",
 		RunE:    c.handleSubscribe,
 		Args: func(cmd *cobra.Command, args []string) error {
+			echo, err := cmd.PersistentFlags().GetBool("echo")
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			if echo {
+				if len(args) != 0 {
+					return errors.New("There must be no argument in echo mode")
+				}
+
+				return nil
+			}
+
 			if len(args) != 1 {
 				return errors.New("There must be only one argument and it must be your local address")
 			}
@@ -95,15 +166,271 @@ func (c *CLI) defineCommands() {
 		},
 	}
 
-	subscribeCmd.PersistentFlags().String("tunnelAddress", "wss://tunnel1.corbado.com/v1", "Address of the Corbado tunnel server")
-	subscribeCmd.PersistentFlags().String("projectID", "", "ID of the project you want to get webhook requests for")
-	subscribeCmd.PersistentFlags().String("cliSecret", "", "CLI secret for the given project ID (can be found at https://app.corbado.com/app/settings/credentials/cli-secret)")
-	subscribeCmd.PersistentFlags().String("credentialFile", "$HOME/.corbado", "Credentials file location")
+	addTunnelFlags(subscribeCmd)
+	addLocalAddressFlags(subscribeCmd)
+	addRedactFlags(subscribeCmd)
 
-	// Root
-	c.rootCmd = &cobra.Command{Use: cliName}
-	c.rootCmd.PersistentFlags().Bool("colors", true, "Defines if colors are used on output")
-	c.rootCmd.AddCommand(loginCmd, logoutCmd, subscribeCmd)
+	subscribeCmd.PersistentFlags().Int("retryAttempts", 1, "Total attempts to deliver a webhook request to the local address (1 disables retries)")
+	subscribeCmd.PersistentFlags().Duration("retryBackoff", 500*time.Millisecond, "Wait time before the first retry (doubles after each retry)")
+	subscribeCmd.PersistentFlags().Duration("retryDeadline", 10*time.Second, "Total time all attempts for a webhook request may take")
+	subscribeCmd.PersistentFlags().StringSlice("retryOn", tunnel.DefaultRetryOn(), "Retryable conditions: refused, dns, reset, timeout, error, status code (e.g. 503) or status class (e.g. 5xx)")
+	subscribeCmd.PersistentFlags().Bool("echo", false, "Answers webhook requests directly and prints them (no local address needed)")
+	subscribeCmd.PersistentFlags().Int("echoStatus", http.StatusOK, "HTTP status of the answer in echo mode")
+	subscribeCmd.PersistentFlags().String("echoBody", "", "Body of the answer in echo mode")
+	subscribeCmd.PersistentFlags().String("echoSaveDir", "", "Directory to save request bodies to in echo mode (disabled if empty)")
+	subscribeCmd.PersistentFlags().Int("maxRequests", 0, "Stops after given number of webhook requests (for CI, 0 means unlimited)")
+	subscribeCmd.PersistentFlags().Duration("duration", 0, "Stops after given duration (for CI, 0 means unlimited)")
+	subscribeCmd.PersistentFlags().StringSlice("failOn", nil, "Exits with an error if a webhook request matched one of the conditions: 5xx, 4xx, status code, timeout, error, refused, dns or reset")
+	subscribeCmd.PersistentFlags().Bool("interactive", false, "Pauses every webhook request to forward it as-is, edit it in $EDITOR, answer it manually or drop it (other requests wait)")
+	subscribeCmd.PersistentFlags().Bool("tui", false, "Shows a full-screen dashboard with all webhook requests instead of one line per webhook request")
+	subscribeCmd.PersistentFlags().Bool("history", false, "Stores every webhook request in the history (see history command)")
+	subscribeCmd.PersistentFlags().String("historyFile", defaultHistoryFile, "History database location")
+	subscribeCmd.PersistentFlags().Duration("historyMaxAge", 7*24*time.Hour, "Deletes webhook requests older than given duration from the history (0 means unlimited)")
+	subscribeCmd.PersistentFlags().Int("historyMaxRecords", 10000, "Maximum number of webhook requests kept in the history (0 means unlimited)")
+	subscribeCmd.PersistentFlags().Bool("validateResponses", true, "Warns if a response does not match what Corbado expects for the action of the webhook request (e.g. authMethods)")
+	subscribeCmd.PersistentFlags().Bool("schemaDrift", false, "Warns if a webhook request body adds, removes or changes the type of a field compared to the schema baseline of its path and action")
+	subscribeCmd.PersistentFlags().String("schemaFile", defaultSchemaFile, "Schema baseline location (inferred from webhook request bodies)")
+	subscribeCmd.PersistentFlags().Bool("schemaFail", false, "Exits with an error if a webhook request body drifted from the schema baseline (for CI, drifts are not added to the baseline then)")
+	subscribeCmd.PersistentFlags().String("exportFile", "", "File to export all webhook requests of the session to (disabled if empty)")
+	subscribeCmd.PersistentFlags().String("exportFormat", export.FormatHAR, "Format of exports (also used by the dashboard hotkey x): har, curl or httpie")
+	subscribeCmd.PersistentFlags().Bool("reconnect", false, "Reconnects to the tunnel server after losing the connection")
+	subscribeCmd.PersistentFlags().String("metricsAddress", "", "Address to serve Prometheus metrics on (e.g. localhost:9100, disabled if empty)")
+	subscribeCmd.PersistentFlags().String("healthAddress", "", "Address to serve the health endpoint on (e.g. localhost:9101, disabled if empty)")
+
+	return subscribeCmd
+}
+
+func (c *CLI) newWaitForCommand() *cobra.Command {
+	waitForCmd := &cobra.Command{
+		Use:     "wait-for <localAddress>",
+		Example: cliName + " wait-for --path /webhook --match body.action=userCreated --timeout 60s http://localhost:8000",
+		Short:   "Forwards webhook requests until a matching one was handled successfully and prints it as JSON",
+		RunE:    c.handleWaitFor,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("There must be only one argument and it must be your local address")
+			}
+
+			return nil
+		},
+	}
+
+	addTunnelFlags(waitForCmd)
+	addLocalAddressFlags(waitForCmd)
+	addRedactFlags(waitForCmd)
+
+	waitForCmd.PersistentFlags().String("path", "", "Path the webhook request must have (any path if empty)")
+	waitForCmd.PersistentFlags().StringSlice("match", nil, "Condition the webhook request must match (e.g. body.data.username=jane@example.com, body.data.users.0.name=jane, headers.X-Event=created or path=/webhook), all must match")
+	waitForCmd.PersistentFlags().Duration("timeout", time.Minute, "Maximum time to wait for a matching webhook request")
+
+	return waitForCmd
+}
+
+func (c *CLI) newTestCommand() *cobra.Command {
+	testCmd := &cobra.Command{
+		Use:     "test <suite.yaml> <localAddress>",
+		Example: cliName + " test webhooks.yaml http://localhost:8000\n" + cliName + " test --junit report.xml webhooks.yaml http://localhost:8000",
+		Short:   "Sends the webhook requests of a test suite to the local address and checks the responses",
+		RunE:    c.handleTest,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("There must be exactly two arguments: your test suite file and your local address")
+			}
+
+			return nil
+		},
+	}
+
+	addLocalAddressFlags(testCmd)
+	testCmd.PersistentFlags().String("junit", "", "File to write a JUnit XML report to (disabled if empty)")
+
+	return testCmd
+}
+
+func (c *CLI) newLoadCommand() *cobra.Command {
+	loadCmd := &cobra.Command{
+		Use:     "load <recordings> <localAddress>",
+		Example: cliName + " load --rps 50 --duration 30s --concurrency 20 ./recordings http://localhost:8000",
+		Short:   "Replays recorded webhook requests against the local address and reports throughput, errors and latencies",
+		Long: "Replays recorded webhook requests against the local address and reports throughput, errors and latencies.\n\n" +
+			"Recordings are a file or directory (all *.json files) with webhook requests ({\"path\":...,\"headers\":{...},\"body\":...}),\n" +
+			"as single object, JSON array or JSON Lines. Path, headers and body may contain templates to make webhook requests unique:\n" +
+			"{{.Seq}} (sequence number), {{.UUID}} (random UUID) and {{.Unix}} (Unix timestamp).",
+		RunE: c.handleLoad,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("There must be exactly two arguments: your recordings and your local address")
+			}
+
+			return nil
+		},
+	}
+
+	addLocalAddressFlags(loadCmd)
+
+	loadCmd.PersistentFlags().Int("rps", 10, "Webhook requests started per second (at most 100000)")
+	loadCmd.PersistentFlags().Duration("duration", 10*time.Second, "Duration of the load test")
+	loadCmd.PersistentFlags().Int("concurrency", 10, "Maximum webhook requests in flight (further ones are skipped)")
+	loadCmd.PersistentFlags().Bool("verbose", false, "Prints a line for every webhook request")
+
+	return loadCmd
+}
+
+func (c *CLI) newHistoryCommand() *cobra.Command {
+	historyCmd := &cobra.Command{
+		Use:   "history",
+		Short: "Lists, shows and searches webhook requests stored by subscribe",
+	}
+	historyCmd.PersistentFlags().String("historyFile", defaultHistoryFile, "History database location")
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Example: cliName + " history list --status 5xx --since \"2006-01-02 12:00\" --until \"2006-01-02 18:00\"",
+		Short:   "Lists stored webhook requests, newest first",
+		Args:    cobra.NoArgs,
+		RunE:    c.handleHistoryList,
+	}
+	addHistoryFilterFlags(listCmd)
+	listCmd.PersistentFlags().StringSlice("match", nil, "Condition the webhook request must match (e.g. body.data.username=jane@example.com, body.data.users.0.name=jane or headers.X-Event=created), all must match")
+
+	showCmd := &cobra.Command{
+		Use:     "show <id>",
+		Example: cliName + " history show 42",
+		Short:   "Shows a stored webhook request with headers and bodies",
+		Args:    cobra.ExactArgs(1),
+		RunE:    c.handleHistoryShow,
+	}
+
+	searchCmd := &cobra.Command{
+		Use:     "search <expression>...",
+		Example: cliName + " history search body.data.username=jane@example.com --since 24h",
+		Short:   "Searches stored webhook requests by JSON body fields, headers or path (all expressions must match)",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    c.handleHistorySearch,
+	}
+	addHistoryFilterFlags(searchCmd)
+
+	exportCmd := &cobra.Command{
+		Use:     "export [id]...",
+		Example: cliName + " history export --format curl --localAddress http://localhost:3000 42\n" + cliName + " history export --status 5xx --since 24h --output failed.har",
+		Short:   "Exports stored webhook requests (given IDs or all matching the filters) as HAR file, curl or HTTPie commands",
+		RunE:    c.handleHistoryExport,
+	}
+	addHistoryFilterFlags(exportCmd)
+	exportCmd.PersistentFlags().StringSlice("match", nil, "Condition the webhook request must match (e.g. body.data.username=jane@example.com), all must match")
+	exportCmd.PersistentFlags().String("format", export.FormatHAR, "Export format: har, curl or httpie (redacted headers are read from CORBADO_WEBHOOK_<HEADER> variables by the commands)")
+	exportCmd.PersistentFlags().String("localAddress", defaultExportAddress, "Local address the exported webhook requests target")
+	exportCmd.PersistentFlags().String("output", "", "File to write to (stdout if empty)")
+
+	genTestCmd := &cobra.Command{
+		Use:     "gen-test <id>",
+		Example: cliName + " history gen-test 42 --lang go --package webhooks --handler \"NewRouter()\" --output webhook_42_test.go",
+		Short:   "Generates a test which sends a stored webhook request to your handler and asserts the recorded response",
+		Args:    cobra.ExactArgs(1),
+		RunE:    c.handleHistoryGenTest,
+	}
+	genTestCmd.PersistentFlags().String("lang", gentest.LangGo, "Language of the test (go)")
+	genTestCmd.PersistentFlags().String("package", "main", "Package of the test file")
+	genTestCmd.PersistentFlags().String("handler", "http.DefaultServeMux", "Go expression of type http.Handler the webhook request is sent to")
+	genTestCmd.PersistentFlags().String("name", "", "Name of the test function (derived from path and ID if empty)")
+	genTestCmd.PersistentFlags().String("output", "", "File to write to (stdout if empty)")
+
+	historyCmd.AddCommand(listCmd, showCmd, searchCmd, exportCmd, genTestCmd)
+
+	return historyCmd
+}
+
+func (c *CLI) newDiffCommand() *cobra.Command {
+	diffCmd := &cobra.Command{
+		Use:     "diff <id> <id> | diff --request <id|file> <localAddress> <localAddress>",
+		Example: cliName + " diff 41 42\n" + cliName + " diff --request 42 --ignore body.data.createdAt http://localhost:8000 http://localhost:8001",
+		Short:   "Compares the responses of two stored webhook requests or of one webhook request replayed against two local addresses",
+		Long: "Compares the responses of two stored webhook requests (history IDs) or of one webhook request replayed against two local addresses.\n\n" +
+			"Status, headers and bodies (structurally if JSON) are compared, differences are printed as dot separated paths\n" +
+			"(e.g. body.data.status or body.data.users.0.name, the same paths --ignore, --redactPath and --match use). The webhook request to replay is a history ID or a recording file (see load).\n" +
+			"History is stored redacted, masked headers of a history ID have to be set with --header.",
+		RunE: c.handleDiff,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.New("There must be exactly two arguments: two history IDs or (with --request) two local addresses")
+			}
+
+			return nil
+		},
+	}
+
+	diffCmd.PersistentFlags().String("historyFile", defaultHistoryFile, "History database location")
+	diffCmd.PersistentFlags().String("request", "", "Webhook request (history ID or recording file) to replay against both local addresses")
+	diffCmd.PersistentFlags().StringArray("header", nil, "Header to set on the replayed webhook request (e.g. \"Authorization: Basic ...\"), needed for headers masked in the history")
+	diffCmd.PersistentFlags().StringSlice("ignore", diff.DefaultIgnore(), "Path to ignore (e.g. headers.Date or body.data.users.*.createdAt, object keys and array indices, * matches every key and index)")
+	addRedactFlags(diffCmd)
+
+	return diffCmd
+}
+
+func (c *CLI) newCodegenCommand() *cobra.Command {
+	codegenCmd := &cobra.Command{
+		Use:     "codegen",
+		Example: cliName + " codegen --lang ts --output webhooks.ts\n" + cliName + " codegen --lang go --package webhooks --output webhooks.go",
+		Short:   "Generates webhook request and response types and handler skeletons from the embedded webhook schema catalog",
+		Args:    cobra.NoArgs,
+		RunE:    c.handleCodegen,
+	}
+	codegenCmd.PersistentFlags().String("lang", codegen.LangGo, "Language of the generated code: ts, go or python")
+	codegenCmd.PersistentFlags().String("package", "webhooks", "Package of generated Go code")
+	codegenCmd.PersistentFlags().String("output", "", "File to write to (stdout if empty)")
+
+	return codegenCmd
+}
+
+func (c *CLI) newInitCommand() *cobra.Command {
+	initCmd := &cobra.Command{
+		Use:     "init [directory]",
+		Example: cliName + " init\n" + cliName + " init --template node --name my-webhooks --projectID pro-1 ./my-webhooks",
+		Short:   "Creates a minimal webhook handler project with corbado.yaml and a Makefile (asks for everything not given as flag)",
+		Long: "Creates a minimal webhook handler project in the directory (current directory by default).\n\n" +
+			"Templates: go (net/http), node (Express) and python (Flask). Next to the webhook handler, a corbado.yaml with the\n" +
+			"project settings and a test suite (see test command) and a Makefile with targets for subscribe and test are created.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: c.handleInit,
+	}
+
+	initCmd.PersistentFlags().String("template", "", "Template of the webhook handler: "+strings.Join(scaffold.Templates(), ", "))
+	initCmd.PersistentFlags().String("name", "", "Name of the project (derived from the directory if empty)")
+	initCmd.PersistentFlags().String("projectID", "", "ID of the project you want to get webhook requests for")
+	initCmd.PersistentFlags().Int("port", 8000, "Port the webhook handler listens on")
+	initCmd.PersistentFlags().Bool("force", false, "Overwrites existing files")
+
+	return initCmd
+}
+
+func addHistoryFilterFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("since", "", "Only webhook requests since given time (e.g. 24h, 7d, 2006-01-02 or \"2006-01-02 15:04\")")
+	cmd.PersistentFlags().String("until", "", "Only webhook requests until given time (same formats as since)")
+	cmd.PersistentFlags().String("path", "", "Only webhook requests whose path contains given value")
+	cmd.PersistentFlags().StringSlice("status", nil, "Only webhook requests matching one of the conditions: status code, status class (e.g. 5xx), timeout, error, refused, dns or reset")
+	cmd.PersistentFlags().Int("limit", 50, "Maximum number of webhook requests listed (0 means unlimited)")
+}
+
+func addTunnelFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("tunnelAddress", "wss://tunnel1.corbado.com/v1", "Address of the Corbado tunnel server")
+	cmd.PersistentFlags().String("projectID", "", "ID of the project you want to get webhook requests for")
+	cmd.PersistentFlags().String("cliSecret", "", "CLI secret for the given project ID (can be found at https://app.corbado.com/app/settings/credentials/cli-secret)")
+	cmd.PersistentFlags().String("credentialFile", "$HOME/.corbado", "Credentials file location")
+}
+
+func addLocalAddressFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("exec", "", "Command which starts your local application (e.g. \"go run ./cmd/server\"), it gets supervised and stopped on exit")
+	cmd.PersistentFlags().StringSlice("watch", nil, "Files or directories to watch, the command given by --exec gets restarted on changes")
+	cmd.PersistentFlags().Bool("wait", false, "Waits until the local address is reachable instead of failing (implied by --exec and --waitURL)")
+	cmd.PersistentFlags().Duration("waitTimeout", time.Minute, "Maximum time to wait for the local address to become reachable")
+	cmd.PersistentFlags().String("waitURL", "", "Health URL of your local application which must answer with a 2xx status before subscribing")
+	cmd.PersistentFlags().Bool("skipCheck", false, "Skips checking if the local address is reachable (for local applications that come and go)")
+}
+
+func addRedactFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringSlice("redactHeader", nil, "Additional header to mask in output (Authorization, Cookie and Set-Cookie are always masked)")
+	cmd.PersistentFlags().StringSlice("redactPath", nil, "Additional JSON body path to mask in output (e.g. data.username or data.users.*.email, object keys and array indices, * matches every key and index, data.password is always masked)")
 }
 
 func (c *CLI) getAnsi() (*ansi.Ansi, error) {
@@ -115,6 +442,51 @@ func (c *CLI) getAnsi() (*ansi.Ansi, error) {
 	return ansi.New(useColors, os.Stdout), nil
 }
 
+func (c *CLI) getRedactor(cmd *cobra.Command) (*redact.Redactor, error) {
+	headers, err := cmd.PersistentFlags().GetStringSlice("redactHeader")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	paths, err := cmd.PersistentFlags().GetStringSlice("redactPath")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return redact.New(headers, paths), nil
+}
+
+// syncWriter serializes writes since output is written from multiple goroutines
+// (e.g. supervised commands and statistics dumps)
+type syncWriter struct {
+	lock sync.Mutex
+	out  io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.out.Write(p)
+}
+
+// redirect writes to given writer instead until the returned function is
+// called (e.g. to the dashboard while it is shown)
+func (w *syncWriter) redirect(out io.Writer) func() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	previous := w.out
+	w.out = out
+
+	return func() {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+
+		w.out = previous
+	}
+}
+
 func (c *CLI) print(a ...any) {
 	if _, err := fmt.Fprint(c.out, a...); err != nil {
 		panic(err)
@@ -133,11 +505,13 @@ func (c *CLI) printf(format string, a ...any) {
 	}
 }
 
-func buildCredentialFilePath(credentialFile string) (string, error) {
-	if credentialFile != "$HOME/.corbado" { //nolint:gosec
+// expandHome replaces a leading $HOME in given file path (the default of all
+// file flags) with the home directory of the user
+func expandHome(file string) (string, error) {
+	if !strings.HasPrefix(file, "$HOME") {
 		// User overwrote flag, just return what he
 		// has set
-		return credentialFile, nil
+		return file, nil
 	}
 
 	homeDir, err := os.UserHomeDir()
@@ -145,5 +519,5 @@ func buildCredentialFilePath(credentialFile string) (string, error) {
 		return "", errors.WithStack(err)
 	}
 
-	return fmt.Sprintf("%s/%s", homeDir, ".corbado"), nil
+	return homeDir + strings.TrimPrefix(file, "$HOME"), nil
 }
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/codegen"
+	"github.com/corbado/cli/pkg/schema"
+)
+
+func (c *CLI) handleCodegen(cmd *cobra.Command, args []string) error {
+	lang, err := cmd.PersistentFlags().GetString("lang")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := codegen.ValidateLang(lang); err != nil {
+		return errors.Errorf("Invalid lang: %s", err.Error())
+	}
+
+	options := &codegen.Options{}
+
+	if options.Package, err = cmd.PersistentFlags().GetString("package"); err != nil {
+		return errors.WithStack(err)
+	}
+
+	output, err := cmd.PersistentFlags().GetString("output")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	actions, err := schema.Actions()
+	if err != nil {
+		return err
+	}
+
+	source, err := codegen.Generate(lang, actions, options)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		c.print(string(source))
+
+		return nil
+	}
+
+	if err := os.WriteFile(output, source, 0o600); err != nil {
+		return errors.WithStack(err)
+	}
+
+	c.printf("Generated types and handlers for %d webhook actions in %s\n", len(actions), output)
+
+	return nil
+}
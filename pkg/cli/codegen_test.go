@@ -0,0 +1,34 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/cli"
+)
+
+func TestCodegen(t *testing.T) {
+	consoleOutput := new(bytes.Buffer)
+	_, _, err := cli.New(consoleOutput).ExecuteWithArgs("codegen", "--lang", "ts")
+	assert.NoError(t, err)
+	assert.Contains(t, consoleOutput.String(), "export interface AuthMethodsRequest {")
+
+	output := filepath.Join(t.TempDir(), "webhooks.py")
+
+	consoleOutput.Reset()
+	_, _, err = cli.New(consoleOutput).ExecuteWithArgs("codegen", "--lang", "python", "--output", output)
+	assert.NoError(t, err)
+	assert.Equal(t, "Generated types and handlers for 2 webhook actions in "+output+"\n", consoleOutput.String())
+
+	source, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Contains(t, string(source), "def handle_webhook(request: WebhookRequest) -> WebhookResponse:")
+
+	_, stderr, err := cli.New(new(bytes.Buffer)).ExecuteWithArgs("codegen", "--lang", "rust")
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "Invalid lang: unsupported language 'rust' (allowed: ts, go, python)")
+}
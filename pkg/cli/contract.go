@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/jsonpath"
+	"github.com/corbado/cli/pkg/schema"
+	"github.com/corbado/cli/pkg/tunnel"
+	"github.com/corbado/cli/pkg/webhook"
+)
+
+// validateResponses warns about responses of the local address which do not
+// match what Corbado expects for the action of the webhook request
+func (c *CLI) validateResponses(cmd *cobra.Command, ansi *ansi.Ansi, tun *tunnel.Tunnel) error {
+	enabled, err := cmd.PersistentFlags().GetBool("validateResponses")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if !enabled {
+		return nil
+	}
+
+	catalog, err := schema.Actions()
+	if err != nil {
+		return err
+	}
+
+	actions := make(map[string]*schema.Action, len(catalog))
+	for _, action := range catalog {
+		actions[action.Name] = action
+	}
+
+	tun.SetValidator(func(exchange *tunnel.Exchange) {
+		action, violations := checkResponse(actions, exchange)
+		if len(violations) == 0 {
+			return
+		}
+
+		c.printf("%s for action %s (ID %s), Corbado cannot process it:\n", ansi.Yellow("Invalid response"), action, exchange.Request.ID)
+
+		for _, violation := range violations {
+			c.printf("  %s\n", violation.String())
+		}
+	})
+
+	return nil
+}
+
+// checkResponse returns the action of the webhook request and the violations
+// of the response, only successful responses for known actions are checked
+func checkResponse(actions map[string]*schema.Action, exchange *tunnel.Exchange) (string, []schema.Violation) {
+	if exchange.Response.Status < 200 || exchange.Response.Status > 299 {
+		return "", nil
+	}
+
+	request, err := webhook.FromWebhookRequest(exchange.Request)
+	if err != nil {
+		return "", nil
+	}
+
+	action, ok := actions[request.Action]
+	if !ok {
+		return "", nil
+	}
+
+	response, err := jsonpath.Parse(exchange.Response.Body)
+	if err != nil {
+		return action.Name, []schema.Violation{{Message: "must be JSON"}}
+	}
+
+	violations := schema.Validate(action.Response, response)
+
+	// The response must belong to the webhook request
+	responseID, _ := jsonpath.Lookup(response, "responseID")
+	if s, ok := responseID.(string); ok && s != request.ID {
+		violations = append(violations, schema.Violation{
+			Path:    "responseID",
+			Message: fmt.Sprintf("must be the ID of the webhook request %q (got %q)", request.ID, s),
+		})
+	}
+
+	return action.Name, violations
+}
@@ -0,0 +1,59 @@
+package cli_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func TestSubscribeValidatesResponses(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/valid":
+			_, _ = w.Write([]byte(`{"responseID":"who-1","data":{"status":"exists"}}`))
+
+		case "/invalid":
+			_, _ = w.Write([]byte(`{"responseID":"who-1","data":{"status":"yes"}}`))
+
+		default:
+			_, _ = w.Write([]byte(`{"data":{"success":"true"}}`))
+		}
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 3)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{
+		{ID: "req-1", Path: "/valid", Body: `{"id":"who-1","projectID":"pro-1","action":"authMethods","data":{"username":"jane"}}`},
+		{ID: "req-2", Path: "/invalid", Body: `{"id":"who-2","projectID":"pro-1","action":"authMethods","data":{"username":"jane"}}`},
+		{ID: "req-3", Path: "/password", Body: `{"id":"who-3","projectID":"pro-1","action":"passwordVerify","data":{"username":"jane","password":"secret"}}`},
+	}, responses)
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, localServer.URL, "--colors=false")
+	assert.NoError(t, err)
+
+	assert.NotContains(t, output, "(ID req-1)")
+	assert.Contains(t, output, "Invalid response for action authMethods (ID req-2), Corbado cannot process it:\n"+
+		"  data.status: must be one of \"exists\", \"not_exists\", \"blocked\" (got \"yes\")\n"+
+		"  responseID: must be the ID of the webhook request \"who-2\" (got \"who-1\")\n")
+	assert.Contains(t, output, "Invalid response for action passwordVerify (ID req-3), Corbado cannot process it:\n"+
+		"  responseID: is required\n"+
+		"  data.success: must be boolean (got string)\n")
+
+	// Responses are sent back unchanged
+	assert.Len(t, responses, 3)
+
+	<-responses
+	tunnelServer = newTunnelServer(t, []*tunnel.WebhookRequest{{ID: "req-2", Path: "/invalid", Body: `{"id":"who-2","action":"authMethods"}`}}, responses)
+	defer tunnelServer.Close()
+
+	output, err = subscribe(t, tunnelServer, localServer.URL, "--validateResponses=false")
+	assert.NoError(t, err)
+	assert.NotContains(t, output, "Invalid response")
+}
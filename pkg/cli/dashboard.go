@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/tui"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// getDashboard returns the full-screen dashboard if enabled (nil otherwise),
+// it replaces the line printed for every webhook request
+func (c *CLI) getDashboard(cmd *cobra.Command, ansi *ansi.Ansi, tun *tunnel.Tunnel, localAddress string) (*tui.TUI, error) {
+	enabled, err := cmd.PersistentFlags().GetBool("tui")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if !enabled {
+		return nil, nil
+	}
+
+	interactive, err := cmd.PersistentFlags().GetBool("interactive")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if interactive || localAddress == "" {
+		return nil, errors.New("Dashboard cannot be combined with interactive or echo mode")
+	}
+
+	if err := tui.Supported(); err != nil {
+		return nil, errors.Errorf("Dashboard not available: %s", err.Error())
+	}
+
+	redactor, err := c.getRedactor(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replays need the local address before the tunnel gets started, messages
+	// of the tunnel (e.g. reconnects) go through the CLI output which shows
+	// them in the status line of the dashboard
+	tun.SetLocalAddress(localAddress)
+	tun.SetQuiet(true)
+	tun.SetOutput(c.out)
+
+	format, err := getExportFormat(cmd, "exportFormat")
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := tui.New(ansi, redactor, tun.Replay, func(exchange *tunnel.Exchange) (string, error) {
+		return exportExchange(exchange, redactor, format, localAddress)
+	})
+	tun.AddObserver(dashboard)
+
+	return dashboard, nil
+}
+
+// startTunnel starts the tunnel, with dashboard until either the tunnel ends
+// or the user quits the dashboard
+func (c *CLI) startTunnel(tun *tunnel.Tunnel, dashboard *tui.TUI, localAddress string) error {
+	if dashboard == nil {
+		return tun.Start(localAddress)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Everything printed while the dashboard is shown (e.g. warnings or
+	// output of the command started by the exec flag) goes to its status line
+	restoreOutput := c.out.redirect(dashboard)
+	defer restoreOutput()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tun.Start(localAddress)
+		cancel()
+	}()
+
+	if err := dashboard.Run(ctx); err != nil {
+		_ = tun.Stop()
+		<-done
+
+		return err
+	}
+
+	if err := tun.Stop(); err != nil {
+		return err
+	}
+
+	return <-done
+}
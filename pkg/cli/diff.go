@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/diff"
+	"github.com/corbado/cli/pkg/load"
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func (c *CLI) handleDiff(cmd *cobra.Command, args []string) error {
+	ansi, err := c.getAnsi()
+	if err != nil {
+		return err
+	}
+
+	ignore, err := cmd.PersistentFlags().GetStringSlice("ignore")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	rules, err := diff.ParseRules(ignore)
+	if err != nil {
+		return errors.Errorf("Invalid ignore: %s", err.Error())
+	}
+
+	request, err := cmd.PersistentFlags().GetString("request")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var a, b *tunnel.Exchange
+	if request == "" {
+		a, b, err = c.getStoredExchanges(cmd, args)
+	} else {
+		a, b, err = c.replayExchanges(cmd, ansi, request, args)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Not a usage error from here on
+	cmd.SilenceUsage = true
+
+	changes := diff.Exchanges(a, b, rules)
+	if len(changes) == 0 {
+		c.println("No differences")
+
+		return nil
+	}
+
+	c.printChanges(ansi, changes)
+
+	return errors.Errorf("Responses differ in %d places", len(changes))
+}
+
+// getStoredExchanges returns the exchanges of the two history IDs
+func (c *CLI) getStoredExchanges(cmd *cobra.Command, ids []string) (*tunnel.Exchange, *tunnel.Exchange, error) {
+	a, err := c.getHistoryRecord(cmd, ids[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b, err := c.getHistoryRecord(cmd, ids[1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.Exchange(), b.Exchange(), nil
+}
+
+// replayExchanges sends the webhook request (history ID or recording file)
+// to both local addresses
+func (c *CLI) replayExchanges(cmd *cobra.Command, ansi *ansi.Ansi, request string, localAddresses []string) (*tunnel.Exchange, *tunnel.Exchange, error) {
+	for _, localAddress := range localAddresses {
+		if vldMsg := c.validateLocalAddressURL(localAddress); vldMsg != "" {
+			return nil, nil, errors.Errorf("Invalid localAddress: %s", vldMsg)
+		}
+	}
+
+	req, err := c.getDiffRequest(cmd, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	redactor, err := c.getRedactor(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exchanges := make([]*tunnel.Exchange, 0, len(localAddresses))
+
+	for _, localAddress := range localAddresses {
+		tun := tunnel.New(ansi, "")
+		tun.SetLocalAddress(localAddress)
+		tun.SetQuiet(true)
+
+		exchange, err := tun.Forward(req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		exchanges = append(exchanges, redactExchange(exchange, redactor))
+	}
+
+	return exchanges[0], exchanges[1], nil
+}
+
+func (c *CLI) getDiffRequest(cmd *cobra.Command, request string) (*tunnel.WebhookRequest, error) {
+	req, err := c.loadDiffRequest(cmd, request)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := cmd.PersistentFlags().GetStringArray("header")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	req, err = overrideHeaders(req, headers)
+	if err != nil {
+		return nil, errors.Errorf("Invalid header: %s", err.Error())
+	}
+
+	// History is stored redacted, replaying masked credentials or body values
+	// would compare two meaningless responses (e.g. 401 from both)
+	if masked := maskedParts(req); len(masked) > 0 {
+		return nil, errors.Errorf("Invalid request: webhook request %s contains redacted values (%s), set masked headers with --header or replay a recording file", request, strings.Join(masked, ", "))
+	}
+
+	return req, nil
+}
+
+func (c *CLI) loadDiffRequest(cmd *cobra.Command, request string) (*tunnel.WebhookRequest, error) {
+	if _, err := strconv.ParseUint(request, 10, 64); err == nil {
+		record, err := c.getHistoryRecord(cmd, request)
+		if err != nil {
+			return nil, err
+		}
+
+		return record.Request, nil
+	}
+
+	recordings, err := load.LoadRecordings(request)
+	if err != nil {
+		return nil, errors.Errorf("Invalid request: %s", err.Error())
+	}
+
+	if len(recordings) != 1 {
+		return nil, errors.Errorf("Invalid request: %s contains %d webhook requests, expected exactly one", request, len(recordings))
+	}
+
+	return recordings[0], nil
+}
+
+// overrideHeaders returns a copy of given webhook request with given headers
+// ("Name: value") set, replacing existing ones case-insensitively
+func overrideHeaders(req *tunnel.WebhookRequest, headers []string) (*tunnel.WebhookRequest, error) {
+	result := *req
+	result.Headers = make(map[string]string, len(req.Headers)+len(headers))
+
+	for name, value := range req.Headers {
+		result.Headers[name] = value
+	}
+
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, errors.Errorf("'%s' must be 'Name: value'", header)
+		}
+
+		for existing := range result.Headers {
+			if strings.EqualFold(existing, name) {
+				delete(result.Headers, existing)
+			}
+		}
+
+		result.Headers[name] = strings.TrimSpace(value)
+	}
+
+	return &result, nil
+}
+
+// maskedParts returns the names of the headers and "body" if they contain
+// redacted values
+func maskedParts(req *tunnel.WebhookRequest) []string {
+	var masked []string
+
+	for name, value := range req.Headers {
+		if value == redact.Mask {
+			masked = append(masked, "header "+name)
+		}
+	}
+
+	sort.Strings(masked)
+
+	if strings.Contains(req.Body, redact.Mask) {
+		masked = append(masked, "body")
+	}
+
+	return masked
+}
+
+func (c *CLI) printChanges(ansi *ansi.Ansi, changes []diff.Change) {
+	for _, change := range changes {
+		switch change.Kind {
+		case diff.KindAdded:
+			c.println(ansi.Green("+ " + change.Path + ": " + diff.Value(change.New)))
+
+		case diff.KindRemoved:
+			c.println(ansi.Red("- " + change.Path + ": " + diff.Value(change.Old)))
+
+		case diff.KindChanged:
+			c.println(ansi.Yellow("~ " + change.Path + ": " + diff.Value(change.Old) + " -> " + diff.Value(change.New)))
+		}
+	}
+}
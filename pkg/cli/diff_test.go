@@ -0,0 +1,128 @@
+package cli_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/cli"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func newDiffServer(status string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"status":"` + status + `","path":"` + r.URL.Path + `"}}`))
+	}))
+}
+
+func TestDiffReplaysAgainstTwoLocalAddresses(t *testing.T) {
+	a := newDiffServer("exists")
+	defer a.Close()
+
+	b := newDiffServer("not_exists")
+	defer b.Close()
+
+	requestFile := filepath.Join(t.TempDir(), "request.json")
+	assert.NoError(t, os.WriteFile(requestFile, []byte(`{"path":"/webhook","body":"{\"action\":\"authMethods\"}"}`), 0o600))
+
+	consoleOutput := new(bytes.Buffer)
+	_, _, err := cli.New(consoleOutput).ExecuteWithArgs("diff", "--colors=false", "--request", requestFile, a.URL, b.URL)
+	assert.EqualError(t, err, "Responses differ in 1 places")
+	assert.Equal(t, "~ body.data.status: \"exists\" -> \"not_exists\"\n", consoleOutput.String())
+
+	consoleOutput.Reset()
+	_, _, err = cli.New(consoleOutput).ExecuteWithArgs("diff", "--colors=false", "--request", requestFile, "--ignore", "headers.Date,headers.Content-Length,body.data.status", a.URL, b.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, "No differences\n", consoleOutput.String())
+}
+
+func TestDiffWithInvalidArguments(t *testing.T) {
+	_, stderr, err := cli.New(new(bytes.Buffer)).ExecuteWithArgs("diff", "1")
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "There must be exactly two arguments")
+
+	_, stderr, err = cli.New(new(bytes.Buffer)).ExecuteWithArgs("diff", "--request", "1", "--historyFile", filepath.Join(t.TempDir(), "history.db"), "http://localhost:8000", "localhost")
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "Invalid localAddress")
+}
+
+func TestDiffComparesStoredExchanges(t *testing.T) {
+	localServer := newDiffServer("exists")
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 2)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/a"}, {ID: "who-2", Path: "/b"}}, responses)
+	defer tunnelServer.Close()
+
+	historyFile := filepath.Join(t.TempDir(), "history.db")
+
+	_, err := subscribe(t, tunnelServer, localServer.URL, "--history", "--historyFile="+historyFile)
+	assert.NoError(t, err)
+
+	consoleOutput := new(bytes.Buffer)
+	_, _, err = cli.New(consoleOutput).ExecuteWithArgs("diff", "--colors=false", "--historyFile="+historyFile, "1", "2")
+	assert.EqualError(t, err, "Responses differ in 1 places")
+	assert.Equal(t, "~ body.data.path: \"/a\" -> \"/b\"\n", consoleOutput.String())
+
+	_, _, err = cli.New(new(bytes.Buffer)).ExecuteWithArgs("diff", "--historyFile="+historyFile, "1", "3")
+	assert.EqualError(t, err, "Webhook request not found in history")
+}
+
+func TestDiffRefusesRedactedRequests(t *testing.T) {
+	// Answers like a handler behind basic auth
+	newAuthServer := func(status string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Basic secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"data":{"status":"` + status + `"}}`))
+		}))
+	}
+
+	a := newAuthServer("exists")
+	defer a.Close()
+
+	b := newAuthServer("not_exists")
+	defer b.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 2)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{
+		{ID: "who-1", Path: "/webhook", Headers: map[string]string{"Authorization": "Basic secret"}, Body: `{"data":{"username":"jane"}}`},
+		{ID: "who-2", Path: "/webhook", Headers: map[string]string{"Authorization": "Basic secret"}, Body: `{"data":{"username":"jane","password":"secret"}}`},
+	}, responses)
+	defer tunnelServer.Close()
+
+	historyFile := filepath.Join(t.TempDir(), "history.db")
+
+	_, err := subscribe(t, tunnelServer, a.URL, "--history", "--historyFile="+historyFile, "--redactPath=data.password")
+	assert.NoError(t, err)
+
+	diff := func(args ...string) (string, error) {
+		consoleOutput := new(bytes.Buffer)
+		_, _, err := cli.New(consoleOutput).ExecuteWithArgs(append([]string{"diff", "--colors=false", "--historyFile=" + historyFile}, args...)...)
+
+		return consoleOutput.String(), err
+	}
+
+	_, err = diff("--request", "1", a.URL, b.URL)
+	assert.EqualError(t, err, "Invalid request: webhook request 1 contains redacted values (header Authorization), set masked headers with --header or replay a recording file")
+
+	output, err := diff("--request", "1", "--header", "Authorization: Basic secret", a.URL, b.URL)
+	assert.EqualError(t, err, "Responses differ in 1 places")
+	assert.Equal(t, "~ body.data.status: \"exists\" -> \"not_exists\"\n", output)
+
+	_, err = diff("--request", "2", "--header", "authorization: Basic secret", a.URL, b.URL)
+	assert.EqualError(t, err, "Invalid request: webhook request 2 contains redacted values (body), set masked headers with --header or replay a recording file")
+
+	_, err = diff("--request", "1", "--header", "Authorization", a.URL, b.URL)
+	assert.EqualError(t, err, "Invalid header: 'Authorization' must be 'Name: value'")
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/jsonpath"
+	"github.com/corbado/cli/pkg/schema"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+const defaultSchemaFile = "$HOME/.corbado_schemas.json"
+
+// driftDetector compares the body of every webhook request to the schema
+// baseline of its path and event and warns about drifts
+type driftDetector struct {
+	ansi     *ansi.Ansi
+	baseline *schema.Baseline
+	file     string
+	fail     bool
+	print    func(format string, a ...any)
+
+	lock     sync.Mutex
+	requests int
+	drifted  int
+}
+
+func (c *CLI) getDriftDetector(cmd *cobra.Command, ansi *ansi.Ansi, tun *tunnel.Tunnel) (*driftDetector, error) {
+	enabled, err := cmd.PersistentFlags().GetBool("schemaDrift")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if !enabled {
+		return nil, nil
+	}
+
+	schemaFile, err := cmd.PersistentFlags().GetString("schemaFile")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	schemaFile, err = expandHome(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	fail, err := cmd.PersistentFlags().GetBool("schemaFail")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	baseline, err := schema.LoadBaseline(schemaFile)
+	if err != nil {
+		return nil, errors.Errorf("Invalid schemaFile: %s", err.Error())
+	}
+
+	d := &driftDetector{
+		ansi:     ansi,
+		baseline: baseline,
+		file:     schemaFile,
+		fail:     fail,
+		print:    c.printf,
+	}
+
+	tun.AddObserver(d)
+
+	c.printf("Checking webhook requests against schema baseline %s\n", schemaFile)
+
+	return d, nil
+}
+
+// Observe checks the body of given exchange (implements tunnel.Observer),
+// bodies which are not JSON are ignored
+func (d *driftDetector) Observe(exchange *tunnel.Exchange) {
+	payload, err := jsonpath.Parse(exchange.Request.Body)
+	if err != nil {
+		return
+	}
+
+	key := schemaKey(exchange.Request.Path, payload)
+
+	// In CI mode drifts are not accepted into the baseline so that every run fails
+	drifts, err := d.baseline.Check(key, payload, !d.fail)
+	if err != nil {
+		d.print("Failed to store schema baseline: %s\n", err.Error())
+	}
+
+	d.lock.Lock()
+	d.requests++
+	if len(drifts) > 0 {
+		d.drifted++
+	}
+	d.lock.Unlock()
+
+	if len(drifts) == 0 {
+		return
+	}
+
+	d.print("%s for %s (ID %s) compared to baseline:\n", d.ansi.Yellow("Schema drift"), key, exchange.Request.ID)
+
+	for _, drift := range drifts {
+		d.print("  %s\n", drift.String())
+	}
+}
+
+// result returns an error if webhook requests drifted and failing is enabled
+func (d *driftDetector) result() error {
+	if d == nil || !d.fail {
+		return nil
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.drifted > 0 {
+		return errors.Errorf("%d of %d webhook requests drifted from the schema baseline (%s)", d.drifted, d.requests, d.file)
+	}
+
+	return nil
+}
+
+// schemaKey identifies the kind of webhook request by path and, if given,
+// the action of the payload (e.g. "/webhook authMethods")
+func schemaKey(path string, payload any) string {
+	if action, ok := jsonpath.Lookup(payload, "action"); ok {
+		if s, ok := action.(string); ok && s != "" {
+			return path + " " + s
+		}
+	}
+
+	return path
+}
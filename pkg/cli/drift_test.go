@@ -0,0 +1,51 @@
+package cli_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func TestSubscribeDetectsSchemaDrift(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer localServer.Close()
+
+	schemaFile := filepath.Join(t.TempDir(), "schemas.json")
+
+	subscribeWith := func(body string, args ...string) (string, error) {
+		responses := make(chan *tunnel.WebhookResponse, 1)
+		tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook", Body: body}}, responses)
+		defer tunnelServer.Close()
+
+		return subscribe(t, tunnelServer, localServer.URL, append([]string{"--colors=false", "--schemaDrift", fmt.Sprintf("--schemaFile=%s", schemaFile)}, args...)...)
+	}
+
+	output, err := subscribeWith(`{"action":"authMethods","data":{"username":"jane"}}`)
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Checking webhook requests against schema baseline "+schemaFile)
+	assert.NotContains(t, output, "Schema drift")
+
+	output, err = subscribeWith(`{"action":"authMethods","data":{"username":1,"age":42}}`, "--schemaFail")
+	assert.EqualError(t, err, fmt.Sprintf("1 of 1 webhook requests drifted from the schema baseline (%s)", schemaFile))
+	assert.Contains(t, output, "Schema drift for /webhook authMethods (ID who-1) compared to baseline:\n  + data.age: number\n  ~ data.username: string -> number\n")
+
+	// Other actions have their own baseline
+	output, err = subscribeWith(`{"action":"passwordVerify","data":{"username":1}}`, "--schemaFail")
+	assert.NoError(t, err)
+	assert.NotContains(t, output, "Schema drift")
+
+	// Without failing, the drift is warned about once and accepted
+	output, err = subscribeWith(`{"action":"authMethods","data":{"username":"jane","age":42}}`)
+	assert.NoError(t, err)
+	assert.Contains(t, output, "+ data.age: number")
+
+	output, err = subscribeWith(`{"action":"authMethods","data":{"username":"jane","age":42}}`, "--schemaFail")
+	assert.NoError(t, err)
+	assert.NotContains(t, output, "Schema drift")
+}
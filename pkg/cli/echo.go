@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/format"
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// newEchoHandler returns a tunnel handler which answers every webhook request
+// with the configured status and body and prints the full request
+func (c *CLI) newEchoHandler(cmd *cobra.Command, ansi *ansi.Ansi, redactor *redact.Redactor) (tunnel.Handler, error) {
+	status, err := cmd.PersistentFlags().GetInt("echoStatus")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if status < 100 || status > 599 {
+		return nil, errors.Errorf("Invalid echoStatus: %d", status)
+	}
+
+	body, err := cmd.PersistentFlags().GetString("echoBody")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	saveDir, err := cmd.PersistentFlags().GetString("echoSaveDir")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if saveDir != "" {
+		if err := os.MkdirAll(saveDir, 0700); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	contentType := "text/plain"
+	if isJSON(body) {
+		contentType = "application/json"
+	}
+
+	return func(exchange *tunnel.Exchange, _ func(exchange *tunnel.Exchange) error) error {
+		req := exchange.Request
+
+		exchange.Response = &tunnel.WebhookResponse{
+			ID:      req.ID,
+			Status:  status,
+			Headers: map[string]string{"Content-Type": contentType},
+			Body:    body,
+		}
+
+		c.printf(
+			"[%s] [%s] Corbado issued request > Received through tunnel > Echo: %s %s (body: %s) > Answered HTTP status %s (body: %s), sent it through tunnel > Corbado got response\n",
+			exchange.Time.Format("2006-01-02 15:04:05"),
+			format.Latency(0),
+			ansi.Bold(http.MethodPost),
+			req.Path,
+			format.Bytes(len(req.Body)),
+			ansi.ColorizeHTTPStatusCode(status),
+			format.Bytes(len(body)),
+		)
+
+		redacted := req.Redacted(redactor)
+		c.printRequest(ansi, redacted)
+
+		if saveDir != "" {
+			fileName, err := saveEchoBody(saveDir, exchange.Time, redacted)
+			if err != nil {
+				c.printf("  Failed to save body: %s\n", err.Error())
+
+				return nil
+			}
+
+			c.printf("  Saved body to %s\n", fileName)
+		}
+
+		return nil
+	}, nil
+}
+
+func saveEchoBody(saveDir string, t time.Time, req *tunnel.WebhookRequest) (string, error) {
+	extension := "txt"
+	if isJSON(req.Body) {
+		extension = "json"
+	}
+
+	fileName := filepath.Join(saveDir, fmt.Sprintf("%s_%s.%s", t.Format("20060102-150405.000"), filepath.Base(req.ID), extension))
+	if err := os.WriteFile(fileName, []byte(req.Body), 0600); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return fileName, nil
+}
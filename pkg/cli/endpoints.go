@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// endpoints collects the handlers served by subscribe per address so that
+// several endpoints (e.g. metrics and health) can share the same address
+type endpoints struct {
+	muxes     map[string]*http.ServeMux
+	addresses []string
+	messages  []string
+}
+
+func newEndpoints() *endpoints {
+	return &endpoints{
+		muxes: make(map[string]*http.ServeMux),
+	}
+}
+
+// handle registers given handler for given pattern on given address, the
+// message is printed once serving started
+func (e *endpoints) handle(address string, pattern string, handler http.Handler, message string) {
+	if _, ok := e.muxes[address]; !ok {
+		e.muxes[address] = http.NewServeMux()
+		e.addresses = append(e.addresses, address)
+	}
+
+	e.muxes[address].Handle(pattern, handler)
+	e.messages = append(e.messages, message)
+}
+
+// serveEndpoints serves Prometheus metrics and the health endpoint of given
+// tunnel if the corresponding address flags are set, the returned function
+// stops serving
+func (c *CLI) serveEndpoints(cmd *cobra.Command, tun *tunnel.Tunnel, localAddress string) (func(), error) {
+	e := newEndpoints()
+
+	if err := c.addMetricsEndpoint(cmd, tun, e); err != nil {
+		return nil, err
+	}
+
+	if err := c.addHealthEndpoint(cmd, tun, localAddress, e); err != nil {
+		return nil, err
+	}
+
+	stops := make([]func(), 0, len(e.addresses))
+	stopAll := func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}
+
+	for _, address := range e.addresses {
+		stop, err := c.startServer(address, e.muxes[address])
+		if err != nil {
+			stopAll()
+
+			return nil, err
+		}
+
+		stops = append(stops, stop)
+	}
+
+	for _, message := range e.messages {
+		c.printf("%s\n", message)
+	}
+
+	return stopAll, nil
+}
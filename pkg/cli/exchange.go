@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// printRequest prints headers and body of given (already redacted) webhook request
+func (c *CLI) printRequest(ansi *ansi.Ansi, req *tunnel.WebhookRequest) {
+	c.printf("  %s %s\n", ansi.Bold("Request"), req.Path)
+	c.printHeadersAndBody(req.Headers, req.Body)
+}
+
+// printResponse prints status, headers and body of given (already redacted) webhook response
+func (c *CLI) printResponse(ansi *ansi.Ansi, resp *tunnel.WebhookResponse) {
+	c.printf("  %s %s\n", ansi.Bold("Response"), ansi.ColorizeHTTPStatusCode(resp.Status))
+	c.printHeadersAndBody(resp.Headers, resp.Body)
+}
+
+func (c *CLI) printHeadersAndBody(headers map[string]string, body string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		c.printf("    %s: %s\n", name, headers[name])
+	}
+
+	if body == "" {
+		c.println("    (empty body)")
+
+		return
+	}
+
+	for _, line := range strings.Split(prettyBody(body), "\n") {
+		c.printf("    %s\n", line)
+	}
+}
+
+// prettyBody indents given body if it is JSON
+func prettyBody(body string) string {
+	buffer := new(bytes.Buffer)
+	if err := json.Indent(buffer, []byte(body), "", "  "); err != nil {
+		return body
+	}
+
+	return buffer.String()
+}
+
+func isJSON(body string) bool {
+	return json.Valid([]byte(body))
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/export"
+	"github.com/corbado/cli/pkg/history"
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// defaultExportAddress is the local address exports target if none is known
+const defaultExportAddress = "http://localhost:8000"
+
+func (c *CLI) handleHistoryExport(cmd *cobra.Command, args []string) error {
+	format, err := getExportFormat(cmd, "format")
+	if err != nil {
+		return err
+	}
+
+	localAddress, err := cmd.PersistentFlags().GetString("localAddress")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	output, err := cmd.PersistentFlags().GetString("output")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	exchanges, err := c.getExportExchanges(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if len(exchanges) == 0 {
+		return errors.New("No webhook requests found")
+	}
+
+	if output == "" {
+		return export.Write(c.out, format, exchanges, localAddress)
+	}
+
+	if err := writeExportFile(output, format, exchanges, localAddress); err != nil {
+		return err
+	}
+
+	c.printf("Exported %d webhook requests to %s\n", len(exchanges), output)
+
+	return nil
+}
+
+// getExportExchanges returns the records with given IDs or (without IDs) all
+// records matching the filter flags, oldest first
+func (c *CLI) getExportExchanges(cmd *cobra.Command, ids []string) ([]*tunnel.Exchange, error) {
+	var records []*history.Record
+
+	if len(ids) > 0 {
+		for _, id := range ids {
+			record, err := c.getHistoryRecord(cmd, id)
+			if err != nil {
+				return nil, errors.Errorf("%s: %s", id, err.Error())
+			}
+
+			records = append(records, record)
+		}
+	} else {
+		filter, err := getHistoryFilter(cmd, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		store, _, err := c.getHistoryStore(cmd, history.Retention{})
+		if err != nil {
+			return nil, err
+		}
+
+		if records, err = store.List(filter); err != nil {
+			return nil, err
+		}
+
+		// Newest first to oldest first
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+
+	exchanges := make([]*tunnel.Exchange, 0, len(records))
+	for _, record := range records {
+		exchanges = append(exchanges, record.Exchange())
+	}
+
+	return exchanges, nil
+}
+
+func getExportFormat(cmd *cobra.Command, flag string) (string, error) {
+	format, err := cmd.PersistentFlags().GetString(flag)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if err := export.ValidateFormat(format); err != nil {
+		return "", errors.Errorf("Invalid %s: %s", flag, err.Error())
+	}
+
+	return format, nil
+}
+
+// exportSession writes every webhook request of the session to the export
+// file (if given) right away so that it is complete even if the CLI gets
+// killed, the returned function closes the file
+func (c *CLI) exportSession(cmd *cobra.Command, tun *tunnel.Tunnel, localAddress string) (func(), error) {
+	file, err := cmd.PersistentFlags().GetString("exportFile")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if file == "" {
+		return func() {}, nil
+	}
+
+	format, err := getExportFormat(cmd, "exportFormat")
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := c.getRedactor(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if localAddress == "" {
+		localAddress = defaultExportAddress
+	}
+
+	exportFile, err := export.Create(file, format, localAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	var lock sync.Mutex
+
+	tun.AddObserver(tunnel.ObserverFunc(func(exchange *tunnel.Exchange) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if err := exportFile.Add(redactExchange(exchange, redactor)); err != nil {
+			c.printf("Failed to export webhook request: %s\n", err.Error())
+		}
+	}))
+
+	return func() {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if err := exportFile.Close(); err != nil {
+			c.printf("Failed to close export file: %s\n", err.Error())
+		}
+	}, nil
+}
+
+// exportExchange writes given exchange to a new file in the current
+// directory, it returns the file name
+func exportExchange(exchange *tunnel.Exchange, redactor *redact.Redactor, format string, localAddress string) (string, error) {
+	file := fmt.Sprintf("webhook-%s-%s.%s", filepath.Base(exchange.Request.ID), exchange.Time.Format("20060102-150405"), export.Extension(format))
+
+	if err := writeExportFile(file, format, []*tunnel.Exchange{redactExchange(exchange, redactor)}, localAddress); err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
+
+func redactExchange(exchange *tunnel.Exchange, redactor *redact.Redactor) *tunnel.Exchange {
+	redacted := *exchange
+	redacted.Request = exchange.Request.Redacted(redactor)
+
+	if exchange.Response != nil {
+		redacted.Response = exchange.Response.Redacted(redactor)
+	}
+
+	return &redacted
+}
+
+func writeExportFile(file string, format string, exchanges []*tunnel.Exchange, localAddress string) error {
+	f, err := export.Create(file, format, localAddress)
+	if err != nil {
+		return err
+	}
+
+	for _, exchange := range exchanges {
+		if err := f.Add(exchange); err != nil {
+			_ = f.Close()
+
+			return err
+		}
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/gentest"
+)
+
+func (c *CLI) handleHistoryGenTest(cmd *cobra.Command, args []string) error {
+	lang, err := cmd.PersistentFlags().GetString("lang")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := gentest.ValidateLang(lang); err != nil {
+		return errors.Errorf("Invalid lang: %s", err.Error())
+	}
+
+	options := &gentest.Options{}
+
+	if options.Package, err = cmd.PersistentFlags().GetString("package"); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if options.Handler, err = cmd.PersistentFlags().GetString("handler"); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if options.Name, err = cmd.PersistentFlags().GetString("name"); err != nil {
+		return errors.WithStack(err)
+	}
+
+	output, err := cmd.PersistentFlags().GetString("output")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	record, err := c.getHistoryRecord(cmd, args[0])
+	if err != nil {
+		return err
+	}
+
+	source, err := gentest.Go(record, options)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		c.print(string(source))
+
+		return nil
+	}
+
+	if err := os.WriteFile(output, source, 0o600); err != nil {
+		return errors.WithStack(err)
+	}
+
+	c.printf("Generated %s in %s\n", options.Name, output)
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/health"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// addHealthEndpoint adds the health endpoint of given tunnel to given
+// endpoints if the health address flag is set, the local address is only
+// checked if there is one (e.g. not in echo mode)
+func (c *CLI) addHealthEndpoint(cmd *cobra.Command, tun *tunnel.Tunnel, localAddress string, e *endpoints) error {
+	healthAddress, err := cmd.PersistentFlags().GetString("healthAddress")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if healthAddress == "" {
+		return nil
+	}
+
+	var localCheck health.LocalCheck
+	if localAddress != "" {
+		localCheck = func() error {
+			return c.checkLocalAddress(localAddress)
+		}
+	}
+
+	h := health.New(localAddress, tun.Status, localCheck)
+
+	e.handle(healthAddress, "/health", h, fmt.Sprintf("Serving health endpoint on http://%s/health", healthAddress))
+
+	return nil
+}
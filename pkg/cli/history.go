@@ -0,0 +1,295 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/format"
+	"github.com/corbado/cli/pkg/history"
+	"github.com/corbado/cli/pkg/match"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+const defaultHistoryFile = "$HOME/.corbado_history.db"
+
+// historyQueueSize is the number of webhook requests waiting to be stored
+// before the tunnel waits for the history
+const historyQueueSize = 256
+
+// getHistoryStore returns the store of the history file flag and the file name
+func (c *CLI) getHistoryStore(cmd *cobra.Command, retention history.Retention) (*history.Store, string, error) {
+	historyFile, err := cmd.Flags().GetString("historyFile")
+	if err != nil {
+		return nil, "", errors.WithStack(err)
+	}
+
+	historyFile, err = expandHome(historyFile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return history.New(historyFile, retention), historyFile, nil
+}
+
+// recordHistory stores every handled webhook request in the history (if
+// enabled), the returned function waits until everything is stored
+func (c *CLI) recordHistory(cmd *cobra.Command, tun *tunnel.Tunnel) (func(), error) {
+	enabled, err := cmd.PersistentFlags().GetBool("history")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if !enabled {
+		return func() {}, nil
+	}
+
+	maxAge, err := cmd.PersistentFlags().GetDuration("historyMaxAge")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	maxRecords, err := cmd.PersistentFlags().GetInt("historyMaxRecords")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if maxAge < 0 || maxRecords < 0 {
+		return nil, errors.New("Invalid history retention: must not be negative")
+	}
+
+	store, historyFile, err := c.getHistoryStore(cmd, history.Retention{MaxAge: maxAge, MaxRecords: maxRecords})
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := c.getRedactor(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	c.printf("Storing webhook requests in history %s\n", historyFile)
+
+	// Stored in the background so that the tunnel does not wait for the disk
+	exchanges := make(chan *tunnel.Exchange, historyQueueSize)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for exchange := range exchanges {
+			// Everything queued meanwhile is stored in the same write, the file
+			// is only locked while writing so history commands can read it
+			batch := []*tunnel.Exchange{exchange}
+			batch = append(batch, drainExchanges(exchanges)...)
+
+			if _, err := store.AddAll(batch, redactor); err != nil {
+				c.printf("Failed to store webhook request in history: %s\n", err.Error())
+			}
+		}
+	}()
+
+	tun.AddObserver(tunnel.ObserverFunc(func(exchange *tunnel.Exchange) {
+		exchanges <- exchange
+	}))
+
+	return func() {
+		close(exchanges)
+		<-done
+	}, nil
+}
+
+// drainExchanges returns all exchanges which are already queued
+func drainExchanges(exchanges <-chan *tunnel.Exchange) []*tunnel.Exchange {
+	var drained []*tunnel.Exchange
+
+	for {
+		select {
+		case exchange, ok := <-exchanges:
+			if !ok {
+				return drained
+			}
+
+			drained = append(drained, exchange)
+
+		default:
+			return drained
+		}
+	}
+}
+
+func (c *CLI) handleHistoryList(cmd *cobra.Command, args []string) error {
+	return c.listHistory(cmd, nil)
+}
+
+func (c *CLI) handleHistorySearch(cmd *cobra.Command, args []string) error {
+	return c.listHistory(cmd, args)
+}
+
+func (c *CLI) listHistory(cmd *cobra.Command, expressions []string) error {
+	ansi, err := c.getAnsi()
+	if err != nil {
+		return err
+	}
+
+	filter, err := getHistoryFilter(cmd, expressions)
+	if err != nil {
+		return err
+	}
+
+	store, _, err := c.getHistoryStore(cmd, history.Retention{})
+	if err != nil {
+		return err
+	}
+
+	records, err := store.List(filter)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		c.println("No webhook requests found")
+
+		return nil
+	}
+
+	c.println(ansi.Bold(fmt.Sprintf("%6s  %-19s  %-13s  %10s  %s", "ID", "TIME", "STATUS", "LATENCY", "PATH")))
+
+	for _, record := range records {
+		c.printf("%6d  %-19s  %-13s  %s  %s\n", record.ID, record.Time.Local().Format("2006-01-02 15:04:05"), recordStatus(record), format.Latency(record.Latency), record.Request.Path)
+	}
+
+	return nil
+}
+
+func (c *CLI) handleHistoryShow(cmd *cobra.Command, args []string) error {
+	ansi, err := c.getAnsi()
+	if err != nil {
+		return err
+	}
+
+	record, err := c.getHistoryRecord(cmd, args[0])
+	if err != nil {
+		return err
+	}
+
+	c.printRecord(ansi, record)
+
+	return nil
+}
+
+func (c *CLI) getHistoryRecord(cmd *cobra.Command, value string) (*history.Record, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("Invalid history ID '%s' (see history list)", value)
+	}
+
+	store, _, err := c.getHistoryStore(cmd, history.Retention{})
+	if err != nil {
+		return nil, err
+	}
+
+	return store.Get(id)
+}
+
+func (c *CLI) printRecord(ansi *ansi.Ansi, record *history.Record) {
+	c.printf(
+		"%s %d (ID %s) at %s, attempts: %d, latency: %s\n",
+		ansi.Bold("Webhook request"),
+		record.ID,
+		record.Request.ID,
+		record.Time.Local().Format("2006-01-02 15:04:05"),
+		record.Attempts,
+		format.Duration(record.Latency),
+	)
+
+	if record.Error != "" {
+		c.printf("  %s %s\n", ansi.Bold("Error"), ansi.Red(record.Error))
+	}
+
+	c.printRequest(ansi, record.Request)
+
+	if record.Response != nil {
+		c.printResponse(ansi, record.Response)
+	}
+}
+
+func recordStatus(record *history.Record) string {
+	switch {
+	case record.TimedOut:
+		return "timeout"
+
+	case record.Response == nil:
+		return "-"
+
+	case record.ErrorKind != "":
+		return fmt.Sprintf("%d (%s)", record.Response.Status, record.ErrorKind)
+
+	default:
+		return strconv.Itoa(record.Response.Status)
+	}
+}
+
+func getHistoryFilter(cmd *cobra.Command, expressions []string) (*history.Filter, error) {
+	filter := &history.Filter{}
+	now := time.Now()
+
+	since, err := cmd.Flags().GetString("since")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if since != "" {
+		if filter.Since, err = history.ParseTime(since, now); err != nil {
+			return nil, errors.Errorf("Invalid since: %s", err.Error())
+		}
+	}
+
+	until, err := cmd.Flags().GetString("until")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if until != "" {
+		if filter.Until, err = history.ParseTime(until, now); err != nil {
+			return nil, errors.Errorf("Invalid until: %s", err.Error())
+		}
+	}
+
+	if filter.Path, err = cmd.Flags().GetString("path"); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if filter.Status, err = cmd.Flags().GetStringSlice("status"); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for _, condition := range filter.Status {
+		if err := tunnel.ValidateCondition(condition); err != nil {
+			return nil, errors.Errorf("Invalid status %s", err.Error())
+		}
+	}
+
+	if filter.Limit, err = cmd.Flags().GetInt("limit"); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if cmd.Flags().Lookup("match") != nil {
+		matches, err := cmd.Flags().GetStringSlice("match")
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		expressions = append(expressions, matches...)
+	}
+
+	if filter.Matchers, err = match.ParseAll(expressions); err != nil {
+		return nil, errors.Errorf("Invalid match: %s", err.Error())
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,168 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/cli"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func TestHistoryListShowSearch(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/failing" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"status":"exists"}}`))
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 2)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{
+		{ID: "who-1", Path: "/webhook", Body: `{"data":{"username":"jane","password":"secret"}}`},
+		{ID: "who-2", Path: "/failing", Body: `{"data":{"username":"john"}}`},
+	}, responses)
+	defer tunnelServer.Close()
+
+	historyFile := filepath.Join(t.TempDir(), "history.db")
+
+	output, err := subscribe(t, tunnelServer, localServer.URL, "--history", "--historyFile="+historyFile)
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Storing webhook requests in history "+historyFile)
+
+	history := func(args ...string) string {
+		consoleOutput := new(bytes.Buffer)
+		_, _, err := cli.New(consoleOutput).ExecuteWithArgs(append([]string{"history", "--colors=false", "--historyFile=" + historyFile}, args...)...)
+		assert.NoError(t, err)
+
+		return consoleOutput.String()
+	}
+
+	output = history("list")
+	assert.Contains(t, output, "/webhook")
+	assert.Contains(t, output, "/failing")
+
+	output = history("list", "--status=5xx", "--since=1h")
+	assert.NotContains(t, output, "/webhook")
+	assert.Contains(t, output, "500")
+
+	output = history("search", "body.data.username=jane")
+	assert.Contains(t, output, "     1  ")
+	assert.NotContains(t, output, "/failing")
+
+	output = history("search", "body.data.username=nobody")
+	assert.Equal(t, "No webhook requests found\n", output)
+
+	output = history("show", "1")
+	assert.Contains(t, output, "Webhook request 1 (ID who-1)")
+	assert.Contains(t, output, `"status": "exists"`)
+	assert.Contains(t, output, `"password": "[REDACTED]"`)
+	assert.NotContains(t, output, "secret")
+
+	output = history("export", "--format=curl", "--localAddress=http://localhost:3000", "1")
+	assert.Contains(t, output, `curl -X POST 'http://localhost:3000/webhook' --data-raw '{"data":{"password":"[REDACTED]","username":"jane"}}'`)
+
+	exportFile := filepath.Join(t.TempDir(), "failed.har")
+	output = history("export", "--status=5xx", "--output="+exportFile)
+	assert.Equal(t, fmt.Sprintf("Exported 1 webhook requests to %s\n", exportFile), output)
+
+	har, err := os.ReadFile(exportFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(har), `"url": "http://localhost:8000/failing"`)
+
+	_, stderr, err := cli.New(new(bytes.Buffer)).ExecuteWithArgs("history", "show", "--historyFile="+historyFile, "42")
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "Webhook request not found in history")
+
+	_, stderr, err = cli.New(new(bytes.Buffer)).ExecuteWithArgs("history", "list", fmt.Sprintf("--historyFile=%s", historyFile), "--since=yesterday")
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "Invalid since")
+}
+
+func TestHistoryListWhileSubscribed(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := newPersistentTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook"}}, responses)
+	defer tunnelServer.Close()
+
+	historyFile := filepath.Join(t.TempDir(), "history.db")
+
+	type listing struct {
+		output string
+		at     time.Time
+	}
+
+	listed := make(chan listing, 1)
+	go func() {
+		<-responses
+
+		// Stored in the background, so list until it shows up
+		output := ""
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+			consoleOutput := new(bytes.Buffer)
+			_, _, err := cli.New(consoleOutput).ExecuteWithArgs("history", "list", "--colors=false", "--historyFile="+historyFile)
+			if err != nil {
+				output = err.Error()
+
+				break
+			}
+
+			if output = consoleOutput.String(); strings.Contains(output, "/webhook") {
+				break
+			}
+		}
+
+		listed <- listing{output: output, at: time.Now()}
+	}()
+
+	_, err := subscribe(t, tunnelServer, localServer.URL, "--history", "--historyFile="+historyFile, "--duration=3s")
+	assert.NoError(t, err)
+
+	ended := time.Now()
+
+	// Listed while subscribe was still running
+	result := <-listed
+	assert.Contains(t, result.output, "/webhook")
+	assert.True(t, result.at.Before(ended))
+}
+
+func TestHistoryGenTest(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook", Body: `{"action":"passwordVerify","data":{"password":"secret"}}`}}, responses)
+	defer tunnelServer.Close()
+
+	historyFile := filepath.Join(t.TempDir(), "history.db")
+
+	_, err := subscribe(t, tunnelServer, localServer.URL, "--history", "--historyFile="+historyFile)
+	assert.NoError(t, err)
+
+	consoleOutput := new(bytes.Buffer)
+	_, _, err = cli.New(consoleOutput).ExecuteWithArgs("history", "gen-test", "--historyFile="+historyFile, "--package=webhooks", "1")
+	assert.NoError(t, err)
+	assert.Contains(t, consoleOutput.String(), "func TestWebhookWebhook1(t *testing.T) {")
+	assert.Contains(t, consoleOutput.String(), "if rec.Code != 202 {")
+	assert.NotContains(t, consoleOutput.String(), "secret")
+
+	_, stderr, err := cli.New(new(bytes.Buffer)).ExecuteWithArgs("history", "gen-test", "--historyFile="+historyFile, "--lang=rust", "1")
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "Invalid lang: unsupported language 'rust' (allowed: go)")
+}
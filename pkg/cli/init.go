@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/scaffold"
+)
+
+func (c *CLI) handleInit(cmd *cobra.Command, args []string) error {
+	ansi, err := c.getAnsi()
+	if err != nil {
+		return err
+	}
+
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	options, exit, err := c.getScaffoldOptions(cmd, ansi, dir)
+	if err != nil || exit {
+		return err
+	}
+
+	force, err := cmd.PersistentFlags().GetBool("force")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	files, err := scaffold.Render(options)
+	if err != nil {
+		return err
+	}
+
+	if err := scaffold.Write(dir, files, force); err != nil {
+		return err
+	}
+
+	c.printf("%s %s project %s in %s:\n", ansi.Green("Created"), options.Template, ansi.Bold(options.Name), dir)
+
+	for _, file := range files {
+		c.printf("  %s\n", file.Path)
+	}
+
+	c.println()
+	c.println("Next steps:")
+
+	if dir != "." {
+		c.printf("  cd %s\n", dir)
+	}
+
+	c.println("  make deps       installs the dependencies of the webhook handler")
+	c.println("  make login      stores the CLI secret of the project (needed once)")
+	c.println("  make subscribe  starts the webhook handler and forwards webhook requests to it")
+	c.println("  make test       runs the test suite of corbado.yaml against the webhook handler")
+
+	return nil
+}
+
+// getScaffoldOptions returns the options from flags and asks for missing
+// ones, it returns true if the user typed exit
+func (c *CLI) getScaffoldOptions(cmd *cobra.Command, ansi *ansi.Ansi, dir string) (*scaffold.Options, bool, error) {
+	options := &scaffold.Options{}
+
+	var err error
+	if options.Template, err = cmd.PersistentFlags().GetString("template"); err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+
+	if options.Name, err = cmd.PersistentFlags().GetString("name"); err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+
+	if options.ProjectID, err = cmd.PersistentFlags().GetString("projectID"); err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+
+	if options.Port, err = cmd.PersistentFlags().GetInt("port"); err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+
+	if options.Port < 1 || options.Port > 65535 {
+		return nil, false, errors.New("Invalid port: must be between 1 and 65535")
+	}
+
+	reader := bufio.NewReader(c.in)
+	exit := false
+
+	if options.Template == "" {
+		question := fmt.Sprintf("Which template do you want to use (%s) [%s]: ", strings.Join(scaffold.Templates(), ", "), scaffold.TemplateGo)
+		options.Template, exit = c.prompt(ansi, reader, question, scaffold.TemplateGo, validationMessage(scaffold.ValidateTemplate))
+
+		if exit {
+			return nil, true, nil
+		}
+	} else if err := scaffold.ValidateTemplate(options.Template); err != nil {
+		return nil, false, errors.Errorf("Invalid template: %s", err.Error())
+	}
+
+	if options.Name == "" {
+		options.Name = defaultProjectName(dir)
+	} else if err := scaffold.ValidateName(options.Name); err != nil {
+		return nil, false, errors.Errorf("Invalid name: %s", err.Error())
+	}
+
+	if options.ProjectID == "" {
+		options.ProjectID, exit = c.readProjectID(ansi, reader)
+		if exit {
+			return nil, true, nil
+		}
+	} else if !c.validateProjectID(options.ProjectID) {
+		return nil, false, errors.New("Invalid projectID, must be of format pro-<number>")
+	}
+
+	return options, false, nil
+}
+
+// defaultProjectName derives the project name from the directory
+func defaultProjectName(dir string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "webhooks"
+	}
+
+	name := strings.ToLower(filepath.Base(absDir))
+	if scaffold.ValidateName(name) != nil {
+		return "webhooks"
+	}
+
+	return name
+}
+
+// validationMessage adapts a validation function to prompt
+func validationMessage(validate func(string) error) func(string) string {
+	return func(answer string) string {
+		if err := validate(answer); err != nil {
+			return strings.ToUpper(err.Error()[:1]) + err.Error()[1:]
+		}
+
+		return ""
+	}
+}
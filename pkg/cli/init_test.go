@@ -0,0 +1,82 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/cli"
+)
+
+func TestInitAsksForMissingOptions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my-webhooks")
+
+	consoleOutput := new(bytes.Buffer)
+
+	c := cli.New(consoleOutput)
+	c.SetIn(strings.NewReader("rust\nnode\ninvalid\npro-1\n"))
+
+	_, _, err := c.ExecuteWithArgs("init", "--colors=false", dir)
+	assert.NoError(t, err)
+
+	output := consoleOutput.String()
+	assert.Contains(t, output, "Which template do you want to use (go, node, python) [go]: Unknown template 'rust' (allowed: go, node, python) (type exit to exit)\n")
+	assert.Contains(t, output, "Please give us your projectID: Invalid projectID, must be of format pro-<number> (type exit to exit)\n")
+	assert.Contains(t, output, "Created node project my-webhooks in "+dir+":\n  Makefile\n  corbado.yaml\n  index.js\n  package.json\n")
+	assert.Contains(t, output, "  cd "+dir+"\n")
+
+	config, err := os.ReadFile(filepath.Join(dir, "corbado.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(config), "  projectID: pro-1\n  template: node\n")
+
+	// Existing files are kept
+	_, _, err = cli.New(new(bytes.Buffer)).ExecuteWithArgs("init", "--template=go", "--projectID=pro-1", dir)
+	assert.EqualError(t, err, filepath.Join(dir, "Makefile")+" already exists (use force to overwrite)")
+}
+
+func TestInitWithFlags(t *testing.T) {
+	dir := t.TempDir()
+
+	consoleOutput := new(bytes.Buffer)
+
+	c := cli.New(consoleOutput)
+	c.SetIn(strings.NewReader(""))
+
+	_, _, err := c.ExecuteWithArgs("init", "--colors=false", "--template=python", "--name=webhooks", "--projectID=pro-1", "--port=5000", dir)
+	assert.NoError(t, err)
+	assert.NotContains(t, consoleOutput.String(), "Please give us")
+
+	makefile, err := os.ReadFile(filepath.Join(dir, "Makefile"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(makefile), "LOCAL_ADDRESS ?= http://localhost:5000\n")
+	assert.Contains(t, string(makefile), "\tcorbado subscribe --exec \"python3 app.py\" $(LOCAL_ADDRESS)\n")
+}
+
+func TestInitExitsOnExit(t *testing.T) {
+	dir := t.TempDir()
+
+	c := cli.New(new(bytes.Buffer))
+	c.SetIn(strings.NewReader("go\nexit\n"))
+
+	_, _, err := c.ExecuteWithArgs("init", dir)
+	assert.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLoginReadsCredentialsInteractively(t *testing.T) {
+	consoleOutput := new(bytes.Buffer)
+
+	c := cli.New(consoleOutput)
+	c.SetIn(strings.NewReader("\npro-1\nexit\n"))
+
+	_, _, err := c.ExecuteWithArgs("login", "--colors=false")
+	assert.NoError(t, err)
+	assert.Contains(t, consoleOutput.String(), "Please give us your projectID: Empty projectID (type exit to exit)\nPlease give us your projectID: Please give us your CLI secret")
+}
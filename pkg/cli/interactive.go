@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// editableRequest is the webhook request as shown in the editor, JSON bodies
+// are embedded as JSON to make them easier to edit
+type editableRequest struct {
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body"`
+}
+
+// newInteractiveHandler returns a tunnel handler which pauses every webhook
+// request until the user decided what to do with it. Webhook requests are
+// handled one after another, all others wait in the tunnel until then.
+func (c *CLI) newInteractiveHandler(ansi *ansi.Ansi, redactor *redact.Redactor) tunnel.Handler {
+	reader := bufio.NewReader(c.in)
+
+	return func(exchange *tunnel.Exchange, forward func(exchange *tunnel.Exchange) error) error {
+		c.println()
+		c.printf("%s webhook request %s (received %s, other webhook requests wait)\n", ansi.Bold("Paused"), exchange.Request.ID, exchange.Time.Format("2006-01-02 15:04:05"))
+		c.printRequest(ansi, exchange.Request.Redacted(redactor))
+
+		for {
+			c.print("[f]orward, [e]dit and forward, [r]espond manually, [d]rop: ")
+
+			choice, err := readLine(reader)
+			if err != nil {
+				// Nobody can answer anymore, just forward
+				c.println("forward (no more input)")
+
+				return c.forwardInteractive(ansi, redactor, exchange, forward)
+			}
+
+			switch strings.ToLower(choice) {
+			case "f", "forward":
+				return c.forwardInteractive(ansi, redactor, exchange, forward)
+
+			case "e", "edit":
+				if err := editRequest(exchange.Request); err != nil {
+					c.println(ansi.Red("Editing failed: " + err.Error()))
+
+					continue
+				}
+
+				c.printRequest(ansi, exchange.Request.Redacted(redactor))
+
+				return c.forwardInteractive(ansi, redactor, exchange, forward)
+
+			case "r", "respond":
+				exchange.Response = c.readResponse(ansi, reader, exchange.Request.ID)
+				c.printResponse(ansi, exchange.Response.Redacted(redactor))
+
+				return nil
+
+			case "d", "drop":
+				c.println("Dropped webhook request (Corbado gets no response)")
+
+				return tunnel.ErrDropped
+
+			default:
+				c.println(ansi.Red("Invalid choice, only f, e, r or d are allowed"))
+			}
+		}
+	}
+}
+
+func (c *CLI) forwardInteractive(ansi *ansi.Ansi, redactor *redact.Redactor, exchange *tunnel.Exchange, forward func(exchange *tunnel.Exchange) error) error {
+	if err := forward(exchange); err != nil {
+		return err
+	}
+
+	c.printResponse(ansi, exchange.Response.Redacted(redactor))
+
+	return nil
+}
+
+// readResponse reads status and body of a manual response
+func (c *CLI) readResponse(ansi *ansi.Ansi, reader *bufio.Reader, id string) *tunnel.WebhookResponse {
+	status := http.StatusOK
+	for {
+		c.printf("Status [%d]: ", http.StatusOK)
+
+		input, err := readLine(reader)
+		if err != nil || input == "" {
+			break
+		}
+
+		parsed, err := strconv.Atoi(input)
+		if err != nil || parsed < 100 || parsed > 599 {
+			c.println(ansi.Red("Invalid status, must be between 100 and 599"))
+
+			continue
+		}
+
+		status = parsed
+
+		break
+	}
+
+	c.print("Body (single line, empty for none): ")
+	body, _ := readLine(reader)
+
+	contentType := "text/plain"
+	if isJSON(body) {
+		contentType = "application/json"
+	}
+
+	return &tunnel.WebhookResponse{
+		ID:      id,
+		Status:  status,
+		Headers: map[string]string{"Content-Type": contentType},
+		Body:    body,
+	}
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", errors.WithStack(err)
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// editRequest opens path, headers and body of given webhook request in the
+// editor of the user ($VISUAL or $EDITOR) and takes over the changes
+func editRequest(req *tunnel.WebhookRequest) error {
+	body := json.RawMessage(req.Body)
+	if !isJSON(req.Body) {
+		encoded, err := json.Marshal(req.Body)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		body = encoded
+	}
+
+	content, err := json.MarshalIndent(&editableRequest{Path: req.Path, Headers: req.Headers, Body: body}, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	file, err := os.CreateTemp("", "corbado-webhook-*.json")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+
+		return errors.WithStack(err)
+	}
+
+	if err := file.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := runEditor(file.Name()); err != nil {
+		return err
+	}
+
+	content, err = os.ReadFile(file.Name())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	edited := &editableRequest{}
+	if err := json.Unmarshal(content, edited); err != nil {
+		return errors.Errorf("invalid JSON: %s", err.Error())
+	}
+
+	if edited.Path == "" {
+		return errors.New("path must not be empty")
+	}
+
+	req.Path = edited.Path
+	req.Headers = edited.Headers
+	req.Body = ""
+
+	if len(edited.Body) > 0 && string(edited.Body) != "null" {
+		// Strings are sent as they are, everything else as (compact) JSON
+		var s string
+		if err := json.Unmarshal(edited.Body, &s); err == nil {
+			req.Body = s
+		} else {
+			// Marshal compacts the indented JSON
+			encoded, err := json.Marshal(edited.Body)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			req.Body = string(encoded)
+		}
+	}
+
+	return nil
+}
+
+func runEditor(fileName string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// Editors like "code --wait" come with arguments
+	args := strings.Fields(editor)
+
+	cmd := exec.Command(args[0], append(args[1:], fileName)...) //nolint:gosec
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return errors.Errorf("editor %s failed: %s", editor, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/cli"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func subscribeInteractive(t *testing.T, tunnelServer *httptest.Server, localAddress string, input string) string {
+	return subscribeInteractiveFrom(t, tunnelServer, localAddress, strings.NewReader(input))
+}
+
+func subscribeInteractiveFrom(t *testing.T, tunnelServer *httptest.Server, localAddress string, input io.Reader) string {
+	consoleOutput := new(bytes.Buffer)
+
+	c := cli.New(consoleOutput)
+	c.SetIn(input)
+
+	_, _, err := c.ExecuteWithArgs(
+		"subscribe",
+		"--projectID=pro-1",
+		"--cliSecret=valid",
+		fmt.Sprintf("--tunnelAddress=ws%s", strings.TrimPrefix(tunnelServer.URL, "http")),
+		"--colors=false",
+		fmt.Sprintf("--historyFile=%s", filepath.Join(t.TempDir(), "history.db")),
+		"--interactive",
+		"--duration=1s",
+		localAddress,
+	)
+	assert.NoError(t, err)
+
+	return consoleOutput.String()
+}
+
+func TestSubscribeInteractive(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 3)
+	tunnelServer := newPersistentTunnelServer(t, []*tunnel.WebhookRequest{
+		{ID: "who-1", Path: "/forward", Body: `{"data":{"password":"secret"}}`},
+		{ID: "who-2", Path: "/respond"},
+		{ID: "who-3", Path: "/drop"},
+	}, responses)
+	defer tunnelServer.Close()
+
+	output := subscribeInteractive(t, tunnelServer, localServer.URL, "x\nf\nr\n201\n{\"ok\":true}\nd\n")
+
+	response := <-responses
+	assert.Equal(t, "who-1", response.ID)
+	assert.Equal(t, http.StatusAccepted, response.Status)
+
+	response = <-responses
+	assert.Equal(t, "who-2", response.ID)
+	assert.Equal(t, http.StatusCreated, response.Status)
+	assert.Equal(t, `{"ok":true}`, response.Body)
+
+	_, ok := <-responses
+	assert.False(t, ok)
+
+	assert.Contains(t, output, "Paused webhook request who-1")
+	assert.Contains(t, output, "Invalid choice")
+	assert.Contains(t, output, "Dropped webhook request")
+	assert.Contains(t, output, `"password": "[REDACTED]"`)
+	assert.NotContains(t, output, "secret")
+}
+
+func TestSubscribeInteractiveAnswersPingsWhilePaused(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer localServer.Close()
+
+	pong := make(chan struct{}, 1)
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		c, err := upgrader.Upgrade(w, r, nil)
+		assert.NoError(t, err)
+		defer c.Close()
+
+		c.SetPongHandler(func(string) error {
+			pong <- struct{}{}
+
+			return nil
+		})
+
+		assert.NoError(t, c.WriteJSON(&tunnel.WebhookRequest{ID: "who-1", Path: "/webhook"}))
+		assert.NoError(t, c.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)))
+
+		for {
+			response := &tunnel.WebhookResponse{}
+			if err := c.ReadJSON(response); err != nil {
+				return
+			}
+
+			responses <- response
+		}
+	}))
+	defer tunnelServer.Close()
+
+	// The user decides only after the tunnel server got its pong
+	input, inputWriter := io.Pipe()
+	ponged := make(chan bool, 1)
+	go func() {
+		select {
+		case <-pong:
+			ponged <- true
+		case <-time.After(2 * time.Second):
+			ponged <- false
+		}
+
+		_, _ = inputWriter.Write([]byte("f\n"))
+	}()
+
+	subscribeInteractiveFrom(t, tunnelServer, localServer.URL, input)
+
+	assert.True(t, <-ponged)
+	assert.Equal(t, http.StatusOK, (<-responses).Status)
+}
+
+func TestSubscribeInteractiveEdit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("editor is simulated with cp")
+	}
+
+	received := make(chan string, 1)
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := new(bytes.Buffer)
+		_, _ = body.ReadFrom(r.Body)
+		received <- r.URL.Path + " " + r.Header.Get("X-Edited") + " " + body.String()
+	}))
+	defer localServer.Close()
+
+	edited := filepath.Join(t.TempDir(), "edited.json")
+	assert.NoError(t, os.WriteFile(edited, []byte(`{"path":"/edited","headers":{"X-Edited":"yes"},"body":{"action": "changed"}}`), 0o600))
+	t.Setenv("VISUAL", "cp "+edited)
+
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := newPersistentTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook", Body: `{"action":"original"}`}}, responses)
+	defer tunnelServer.Close()
+
+	subscribeInteractive(t, tunnelServer, localServer.URL, "e\n")
+
+	assert.Equal(t, `/edited yes {"action":"changed"}`, <-received)
+}
+
+func TestSubscribeInteractiveWithEcho(t *testing.T) {
+	_, stderr, err := cli.New(new(bytes.Buffer)).ExecuteWithArgs("subscribe", "--projectID=pro-1", "--cliSecret=valid", "--echo", "--interactive")
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "Interactive mode needs a local address")
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// limits bounds a subscription (for CI) by number of webhook requests and
+// duration and remembers webhook requests matching the fail conditions
+type limits struct {
+	tun         *tunnel.Tunnel
+	maxRequests int
+	duration    time.Duration
+	failOn      []string
+
+	lock     sync.Mutex
+	requests int
+	failures int
+	timer    *time.Timer
+	stopOnce sync.Once
+	print    func(format string, a ...any)
+}
+
+func (c *CLI) getLimits(cmd *cobra.Command, tun *tunnel.Tunnel) (*limits, error) {
+	maxRequests, err := cmd.PersistentFlags().GetInt("maxRequests")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if maxRequests < 0 {
+		return nil, errors.New("Invalid maxRequests: must not be negative")
+	}
+
+	duration, err := cmd.PersistentFlags().GetDuration("duration")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if duration < 0 {
+		return nil, errors.New("Invalid duration: must not be negative")
+	}
+
+	failOn, err := cmd.PersistentFlags().GetStringSlice("failOn")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for _, condition := range failOn {
+		if err := tunnel.ValidateCondition(condition); err != nil {
+			return nil, errors.Errorf("Invalid failOn condition %s", err.Error())
+		}
+	}
+
+	l := &limits{
+		tun:         tun,
+		maxRequests: maxRequests,
+		duration:    duration,
+		failOn:      failOn,
+		print:       c.printf,
+	}
+
+	tun.AddObserver(l)
+
+	return l, nil
+}
+
+// start starts the duration timer, it must be called after the tunnel is connected
+func (l *limits) start() {
+	if l.duration == 0 {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.timer = time.AfterFunc(l.duration, func() {
+		l.stop("Duration of %s elapsed, stopping\n", l.duration)
+	})
+}
+
+// Observe counts given exchange (implements tunnel.Observer)
+func (l *limits) Observe(exchange *tunnel.Exchange) {
+	l.lock.Lock()
+	l.requests++
+	requests := l.requests
+
+	for _, condition := range l.failOn {
+		if tunnel.MatchCondition(condition, exchange) {
+			l.failures++
+
+			break
+		}
+	}
+	l.lock.Unlock()
+
+	if l.maxRequests > 0 && requests >= l.maxRequests {
+		l.stop("Reached maximum of %d webhook requests, stopping\n", l.maxRequests)
+	}
+}
+
+func (l *limits) stop(format string, a ...any) {
+	l.stopOnce.Do(func() {
+		l.print(format, a...)
+
+		if err := l.tun.Stop(); err != nil {
+			l.print("Failed to stop the tunnel: %+v\n", err)
+		}
+	})
+}
+
+// result returns an error if webhook requests matched the fail conditions
+func (l *limits) result() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+
+	if l.failures > 0 {
+		return errors.Errorf("%d of %d webhook requests matched failOn (%s)", l.failures, l.requests, strings.Join(l.failOn, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/format"
+	"github.com/corbado/cli/pkg/load"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func (c *CLI) handleLoad(cmd *cobra.Command, args []string) error {
+	ansi, err := c.getAnsi()
+	if err != nil {
+		return err
+	}
+
+	recordings, err := load.LoadRecordings(args[0])
+	if err != nil {
+		return errors.Errorf("Invalid recordings: %s", err.Error())
+	}
+
+	localAddress := args[1]
+	vldMsg := c.validateLocalAddressURL(localAddress)
+	if vldMsg != "" {
+		return errors.Errorf("Invalid localAddress: %s", vldMsg)
+	}
+
+	config, err := c.getLoadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	verbose, err := cmd.PersistentFlags().GetBool("verbose")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	cleanupTarget, err := c.prepareLocalAddress(cmd, ansi, localAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanupTarget()
+
+	tun := tunnel.New(ansi, "")
+	tun.SetLocalAddress(localAddress)
+	tun.SetQuiet(!verbose)
+
+	// Ctrl+C stops early but still prints the report
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c.printf(
+		"Sending %d webhook requests per second for %s to %s (concurrency: %d, recordings: %d) ...\n",
+		config.RPS,
+		config.Duration,
+		ansi.Bold(localAddress),
+		config.Concurrency,
+		len(recordings),
+	)
+
+	report, err := load.Run(ctx, config, recordings, tun)
+	if err != nil {
+		return err
+	}
+
+	c.printLoadReport(ansi, report)
+
+	return nil
+}
+
+func (c *CLI) getLoadConfig(cmd *cobra.Command) (*load.Config, error) {
+	rps, err := cmd.PersistentFlags().GetInt("rps")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	duration, err := cmd.PersistentFlags().GetDuration("duration")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	concurrency, err := cmd.PersistentFlags().GetInt("concurrency")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	config := &load.Config{
+		RPS:         rps,
+		Duration:    duration,
+		Concurrency: concurrency,
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, errors.Errorf("Invalid load config: %s", err.Error())
+	}
+
+	return config, nil
+}
+
+func (c *CLI) printLoadReport(ansi *ansi.Ansi, report *load.Report) {
+	c.println()
+	c.println(ansi.Bold("Load test results"))
+	c.printf("  Duration:   %s\n", report.Duration.Round(time.Millisecond))
+	c.printf("  Webhooks:   %d sent, %d skipped (concurrency limit reached)\n", report.Sent, report.Skipped)
+	c.printf("  Throughput: %.1f/s\n", report.Throughput)
+	c.printf("  Errors:     %d (%.1f%%)\n", report.Errors, report.ErrorRate*100)
+
+	if len(report.StatusClasses) == 0 {
+		return
+	}
+
+	c.printf("  Status:     %s\n", formatCounts(report.StatusClasses))
+	c.printf(
+		"  Latency:    p50 %s, p90 %s, p95 %s, p99 %s, max %s\n",
+		format.Duration(report.P50Latency),
+		format.Duration(report.P90Latency),
+		format.Duration(report.P95Latency),
+		format.Duration(report.P99Latency),
+		format.Duration(report.MaxLatency),
+	)
+}
@@ -0,0 +1,41 @@
+package cli_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/cli"
+)
+
+func TestLoadPrintsReport(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer localServer.Close()
+
+	recordings := filepath.Join(t.TempDir(), "recordings.json")
+	assert.NoError(t, os.WriteFile(recordings, []byte(`{"path":"/webhook","body":"{\"id\":\"{{.UUID}}\"}"}`), 0o600))
+
+	consoleOutput := new(bytes.Buffer)
+	_, _, err := cli.New(consoleOutput).ExecuteWithArgs("load", "--colors=false", "--rps=50", "--duration=200ms", recordings, localServer.URL)
+	assert.NoError(t, err)
+
+	output := consoleOutput.String()
+	assert.Contains(t, output, "Load test results")
+	assert.Contains(t, output, "Errors:     0 (0.0%)")
+	assert.Contains(t, output, "Latency:    p50")
+}
+
+func TestLoadWithInvalidConfig(t *testing.T) {
+	recordings := filepath.Join(t.TempDir(), "recordings.json")
+	assert.NoError(t, os.WriteFile(recordings, []byte(`{"path":"/webhook"}`), 0o600))
+
+	_, _, err := cli.New(new(bytes.Buffer)).ExecuteWithArgs("load", "--concurrency=0", recordings, "http://localhost:1")
+	assert.EqualError(t, err, "Invalid load config: concurrency must be at least 1")
+}
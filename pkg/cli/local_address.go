@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/supervisor"
+)
+
+// prepareLocalAddress makes sure the local address is reachable before the
+// tunnel gets connected (checks once, waits for it or skips the check), the
+// returned function cleans up (e.g. stops the command started by the exec flag)
+func (c *CLI) prepareLocalAddress(cmd *cobra.Command, ansi *ansi.Ansi, localAddress string) (func(), error) {
+	command, err := cmd.PersistentFlags().GetString("exec")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	wait, err := cmd.PersistentFlags().GetBool("wait")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	waitTimeout, err := cmd.PersistentFlags().GetDuration("waitTimeout")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	waitURL, err := cmd.PersistentFlags().GetString("waitURL")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	skipCheck, err := cmd.PersistentFlags().GetBool("skipCheck")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if waitURL != "" {
+		if vldMsg := c.validateWaitURL(waitURL); vldMsg != "" {
+			return nil, errors.Errorf("Invalid waitURL: %s", vldMsg)
+		}
+	}
+
+	cleanup := func() {}
+	if command != "" {
+		cleanup, err = c.startExec(cmd, ansi, command)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case skipCheck:
+		err = nil
+
+	case wait || waitURL != "" || command != "":
+		err = c.waitForLocalAddress(ansi, localAddress, waitURL, waitTimeout)
+
+	default:
+		if err = c.checkLocalAddress(localAddress); err != nil {
+			err = errors.Errorf("Invalid localAddress: %s", err.Error())
+		}
+	}
+
+	if err != nil {
+		cleanup()
+
+		return nil, err
+	}
+
+	return cleanup, nil
+}
+
+// waitForLocalAddress waits until the local address (or the wait URL if
+// given) is reachable, it can be interrupted with Ctrl-C
+func (c *CLI) waitForLocalAddress(ansi *ansi.Ansi, localAddress string, waitURL string, timeout time.Duration) error {
+	target := localAddress
+	check := func() error {
+		return c.checkLocalAddress(localAddress)
+	}
+
+	if waitURL != "" {
+		target = waitURL
+		check = func() error {
+			return c.checkURL(waitURL)
+		}
+	}
+
+	c.printf("Waiting for %s to become reachable (timeout %s) ...\n", ansi.Bold(target), timeout)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := c.waitFor(ctx, check, timeout); err != nil {
+		return err
+	}
+
+	c.printf("%s is reachable\n", ansi.Bold(target))
+
+	return nil
+}
+
+// startExec starts and supervises given command (restarts it on crash and on
+// changes of the watched files), the returned function stops it
+func (c *CLI) startExec(cmd *cobra.Command, ansi *ansi.Ansi, command string) (func(), error) {
+	watch, err := cmd.PersistentFlags().GetStringSlice("watch")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	s := supervisor.New(command, c.out, ansi.Bold("[exec]")+" ")
+	if err := s.Start(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if len(watch) > 0 {
+		go supervisor.Watch(ctx, watch, time.Second, func() {
+			c.printf("%s detected file changes in %v\n", ansi.Bold("[exec]"), watch)
+
+			if err := s.Restart(); err != nil {
+				c.printf("Failed to restart '%s': %+v\n", command, err)
+			}
+		})
+	}
+
+	return func() {
+		cancel()
+
+		if err := s.Stop(); err != nil {
+			c.printf("Failed to stop '%s': %+v\n", command, err)
+		}
+	}, nil
+}
@@ -25,10 +25,12 @@ func (c *CLI) handleLogin(cmd *cobra.Command, _ []string) error {
 		return errors.WithStack(err)
 	}
 
+	reader := bufio.NewReader(c.in)
+
 	if projectID == "" {
 		// No projectID given as flag, read interactively
 		exit := false
-		projectID, exit = c.readProjectID(ansi)
+		projectID, exit = c.readProjectID(ansi, reader)
 		if exit {
 			return nil
 		}
@@ -44,7 +46,7 @@ func (c *CLI) handleLogin(cmd *cobra.Command, _ []string) error {
 	if cliSecret == "" {
 		// No cliSecret given as flag, read interactively
 		exit := false
-		cliSecret, exit = c.readCliSecret(ansi)
+		cliSecret, exit = c.readCliSecret(ansi, reader)
 		if exit {
 			return nil
 		}
@@ -99,55 +101,64 @@ func (c *CLI) handleLogin(cmd *cobra.Command, _ []string) error {
 	return nil
 }
 
-func (c *CLI) readProjectID(ansi *ansi.Ansi) (string, bool) {
-	projectID := ""
-	for {
-		c.print("Please give us your projectID: ")
-		_, _ = fmt.Scanln(&projectID)
-
+func (c *CLI) readProjectID(ansi *ansi.Ansi, reader *bufio.Reader) (string, bool) {
+	return c.prompt(ansi, reader, "Please give us your projectID: ", "", func(projectID string) string {
 		if projectID == "" {
-			c.println(ansi.Red("Empty projectID (type exit to exit)"))
-			continue
-		}
-
-		if projectID == exitCommand {
-			return "", true
+			return "Empty projectID"
 		}
 
 		if !c.validateProjectID(projectID) {
-			c.println(ansi.Red("Invalid projectID, must be of format pro-<number> (type exit to exit)"))
-			continue
+			return "Invalid projectID, must be of format pro-<number>"
 		}
 
-		break
-	}
+		return ""
+	})
+}
+
+func (c *CLI) readCliSecret(ansi *ansi.Ansi, reader *bufio.Reader) (string, bool) {
+	return c.prompt(ansi, reader, "Please give us your CLI secret (can be found at https://app.corbado.com/app/settings/credentials/cli-secret): ", "", func(cliSecret string) string {
+		if cliSecret == "" {
+			return "Empty CLI secret"
+		}
 
-	return projectID, false
+		return ""
+	})
 }
 
-func (c *CLI) readCliSecret(ansi *ansi.Ansi) (string, bool) {
-	cliSecret := ""
+// prompt asks given question until validate accepts the answer (it returns an
+// error message otherwise), an empty answer is replaced by defaultValue. The
+// returned bool is true if the user typed exit or the input ended.
+func (c *CLI) prompt(ansi *ansi.Ansi, reader *bufio.Reader, question string, defaultValue string, validate func(answer string) string) (string, bool) {
 	for {
-		c.print("Please give us your CLI secret (can be found at https://app.corbado.com/app/settings/credentials/cli-secret): ")
-		_, _ = fmt.Scanln(&cliSecret)
+		c.print(question)
 
-		if cliSecret == "" {
-			c.println(ansi.Red("Empty CLI secret (type exit to exit)"))
-			continue
+		answer, err := readLine(reader)
+		if err != nil {
+			c.println()
+
+			return "", true
 		}
 
-		if cliSecret == exitCommand {
+		if answer == exitCommand {
 			return "", true
 		}
 
-		break
-	}
+		if answer == "" {
+			answer = defaultValue
+		}
+
+		if msg := validate(answer); msg != "" {
+			c.println(ansi.Red(msg + " (type exit to exit)"))
+
+			continue
+		}
 
-	return cliSecret, false
+		return answer, false
+	}
 }
 
 func writeCredentialFile(credentialFile string, projectID string, cliSecret string) (string, error) {
-	credentialFilePath, err := buildCredentialFilePath(credentialFile)
+	credentialFilePath, err := expandHome(credentialFile)
 	if err != nil {
 		return "", err
 	}
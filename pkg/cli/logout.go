@@ -52,7 +52,7 @@ func (c *CLI) handleLogout(cmd *cobra.Command, _ []string) error {
 		return errors.WithStack(err)
 	}
 
-	credentialFilePath, err := buildCredentialFilePath(credentialFile)
+	credentialFilePath, err := expandHome(credentialFile)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/metrics"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// addMetricsEndpoint adds Prometheus metrics of given tunnel to given
+// endpoints if the metrics address flag is set
+func (c *CLI) addMetricsEndpoint(cmd *cobra.Command, tun *tunnel.Tunnel, e *endpoints) error {
+	metricsAddress, err := cmd.PersistentFlags().GetString("metricsAddress")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if metricsAddress == "" {
+		return nil
+	}
+
+	m := metrics.New(tun.Status)
+	tun.AddObserver(m)
+
+	e.handle(metricsAddress, "/metrics", m, fmt.Sprintf("Serving Prometheus metrics on http://%s/metrics", metricsAddress))
+
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package cli
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func notifyDumpSignal(ch chan<- os.Signal) bool {
+	signal.Notify(ch, syscall.SIGUSR1)
+
+	return true
+}
@@ -0,0 +1,12 @@
+//go:build windows
+
+package cli
+
+import (
+	"os"
+)
+
+// notifyDumpSignal does nothing since Windows has no SIGUSR1
+func notifyDumpSignal(_ chan<- os.Signal) bool {
+	return false
+}
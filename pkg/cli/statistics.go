@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/format"
+	"github.com/corbado/cli/pkg/stats"
+)
+
+// dumpStatisticsOnSignal prints the statistics every time the dump signal
+// (SIGUSR1, not available on Windows) is received. The returned function
+// stops listening.
+func (c *CLI) dumpStatisticsOnSignal(ansi *ansi.Ansi, statistics *stats.Stats) func() {
+	ch := make(chan os.Signal, 1)
+	if !notifyDumpSignal(ch) {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for range ch {
+			c.printStatistics(ansi, statistics.Summary())
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(ch)
+		<-done
+	}
+}
+
+func (c *CLI) printStatistics(ansi *ansi.Ansi, summary *stats.Summary) {
+	c.println()
+	c.println(ansi.Bold("Session statistics"))
+	c.printf("  Duration:   %s (since %s)\n", summary.Duration.Round(time.Second), summary.Started.Format("2006-01-02 15:04:05"))
+	c.printf("  Webhooks:   %d (timeouts: %d, errors: %d)\n", summary.Total, summary.Timeouts, summary.Errors)
+
+	if summary.Total == 0 {
+		return
+	}
+
+	c.printf("  Status:     %s\n", formatCounts(summary.StatusClasses))
+	c.printf("  Latency:    min %s, avg %s, p95 %s\n", format.Duration(summary.MinLatency), format.Duration(summary.AvgLatency), format.Duration(summary.P95Latency))
+	c.printf("  Bytes:      in %s, out %s\n", format.Bytes(summary.BytesIn), format.Bytes(summary.BytesOut))
+	c.println("  Paths:")
+
+	for _, path := range stats.SortedKeys(summary.Paths) {
+		c.printf("    %6d  %s\n", summary.Paths[path], path)
+	}
+}
+
+func formatCounts(counts map[string]int) string {
+	result := ""
+	for _, key := range stats.SortedKeys(counts) {
+		if result != "" {
+			result += ", "
+		}
+
+		result += fmt.Sprintf("%s: %d", key, counts[key])
+	}
+
+	return result
+}
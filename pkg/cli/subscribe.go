@@ -4,6 +4,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/stats"
 	"github.com/corbado/cli/pkg/tunnel"
 )
 
@@ -13,71 +15,263 @@ func (c *CLI) handleSubscribe(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	localAddress := args[0]
+	localAddress := ""
+	if len(args) > 0 {
+		localAddress = args[0]
 
-	vldMsg := c.validateLocalAddress(localAddress)
-	if vldMsg != "" {
-		return errors.Errorf("Invalid localAddress: %s", vldMsg)
+		vldMsg := c.validateLocalAddressURL(localAddress)
+		if vldMsg != "" {
+			return errors.Errorf("Invalid localAddress: %s", vldMsg)
+		}
+	}
+
+	tunnelAddress, projectID, cliSecret, err := c.getTunnelCredentials(cmd)
+	if err != nil {
+		return err
 	}
 
+	tun := tunnel.New(ansi, tunnelAddress)
+	tun.SetOutput(c.out)
+	if err := c.configureTunnel(cmd, tun); err != nil {
+		return err
+	}
+
+	closeHistory, err := c.recordHistory(cmd, tun)
+	if err != nil {
+		return err
+	}
+	defer closeHistory()
+
+	if err := c.validateResponses(cmd, ansi, tun); err != nil {
+		return err
+	}
+
+	cleanupTarget, err := c.prepareTarget(cmd, ansi, tun, localAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanupTarget()
+
+	closeExport, err := c.exportSession(cmd, tun, localAddress)
+	if err != nil {
+		return err
+	}
+	defer closeExport()
+
+	stopEndpoints, err := c.serveEndpoints(cmd, tun, localAddress)
+	if err != nil {
+		return err
+	}
+	defer stopEndpoints()
+
+	ciLimits, err := c.getLimits(cmd, tun)
+	if err != nil {
+		return err
+	}
+
+	drift, err := c.getDriftDetector(cmd, ansi, tun)
+	if err != nil {
+		return err
+	}
+
+	dashboard, err := c.getDashboard(cmd, ansi, tun, localAddress)
+	if err != nil {
+		return err
+	}
+
+	connected, err := c.connectTunnel(ansi, tun, tunnelAddress, localAddress, projectID, cliSecret)
+	if err != nil || !connected {
+		return err
+	}
+
+	statistics := stats.New()
+	tun.AddObserver(statistics)
+
+	stopDump := c.dumpStatisticsOnSignal(ansi, statistics)
+	defer func() {
+		stopDump()
+		c.printStatistics(ansi, statistics.Summary())
+	}()
+
+	ciLimits.start()
+
+	if err := c.startTunnel(tun, dashboard, localAddress); err != nil {
+		if err != tunnel.ErrConnectionClosed {
+			return err
+		}
+
+		c.println(err.Error())
+	}
+
+	if err := ciLimits.result(); err != nil {
+		// Not a usage error, just report which webhook requests failed
+		cmd.SilenceUsage = true
+
+		return err
+	}
+
+	if err := drift.result(); err != nil {
+		cmd.SilenceUsage = true
+
+		return err
+	}
+
+	return nil
+}
+
+// getTunnelCredentials returns the tunnel address and the (validated)
+// credentials from flags, environment or credentials file
+func (c *CLI) getTunnelCredentials(cmd *cobra.Command) (string, string, string, error) {
 	tunnelAddress, err := cmd.PersistentFlags().GetString("tunnelAddress")
 	if err != nil {
-		return errors.WithStack(err)
+		return "", "", "", errors.WithStack(err)
 	}
 
 	credentialFile, err := cmd.PersistentFlags().GetString("credentialFile")
 	if err != nil {
-		return errors.WithStack(err)
+		return "", "", "", errors.WithStack(err)
 	}
 
-	credentialFilePath, err := buildCredentialFilePath(credentialFile)
+	credentialFilePath, err := expandHome(credentialFile)
 	if err != nil {
-		return err
+		return "", "", "", err
 	}
 
 	projectID, cliSecret, err := c.getCredentials(NewFlagCredentials(cmd), NewEnvCredentials(), NewFileCredentials(credentialFilePath))
 	if err != nil {
-		return err
+		return "", "", "", err
 	}
 
 	if !c.validateProjectID(projectID) {
-		return errors.New("Invalid projectID")
+		return "", "", "", errors.New("Invalid projectID")
 	}
 
-	tun := tunnel.New(ansi, tunnelAddress)
+	return tunnelAddress, projectID, cliSecret, nil
+}
 
-	c.printf("Subscribing to tunnel server (%s) to get webhook requests for %s ... ", tunnelAddress, ansi.Bold(localAddress))
+// connectTunnel connects given tunnel, it returns false if the tunnel server
+// refused the connection (already printed)
+func (c *CLI) connectTunnel(ansi *ansi.Ansi, tun *tunnel.Tunnel, tunnelAddress string, localAddress string, projectID string, cliSecret string) (bool, error) {
+	target := localAddress
+	if target == "" {
+		target = "echo mode"
+	}
+
+	c.printf("Subscribing to tunnel server (%s) to get webhook requests for %s ... ", tunnelAddress, ansi.Bold(target))
 	if err := tun.Connect(projectID, cliSecret); err != nil {
 		switch err {
 		case tunnel.ErrUnauthorized:
 			c.println(ansi.Bold(ansi.Red("failed (invalid credentials)!")))
 
-			return nil
+			return false, nil
 
 		case tunnel.ErrSessionExists:
 			c.println(ansi.Bold(ansi.Red("failed (another CLI is already connected)!")))
 
-			return nil
+			return false, nil
 
 		case tunnel.ErrInternal:
 			c.println(ansi.Bold(ansi.Red("failed (internal server error)!")))
 
-			return nil
+			return false, nil
 		}
 
-		return err
+		return false, err
 	}
 	c.println(ansi.Bold(ansi.Green("success!")))
 
-	if err := tun.Start(localAddress); err != nil {
-		if err == tunnel.ErrConnectionClosed {
-			c.println(err.Error())
+	return true, nil
+}
+
+// prepareTarget prepares where webhook requests go to: answered directly in
+// echo mode or forwarded to the (reachable) local address, in interactive mode
+// after the user decided what to do
+func (c *CLI) prepareTarget(cmd *cobra.Command, ansi *ansi.Ansi, tun *tunnel.Tunnel, localAddress string) (func(), error) {
+	interactive, err := cmd.PersistentFlags().GetBool("interactive")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	redactor, err := c.getRedactor(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if localAddress != "" {
+		cleanup, err := c.prepareLocalAddress(cmd, ansi, localAddress)
+		if err != nil {
+			return nil, err
+		}
 
-			return nil
+		if interactive {
+			tun.SetHandler(c.newInteractiveHandler(ansi, redactor))
 		}
 
+		return cleanup, nil
+	}
+
+	if interactive {
+		return nil, errors.New("Interactive mode needs a local address (not possible in echo mode)")
+	}
+
+	handler, err := c.newEchoHandler(cmd, ansi, redactor)
+	if err != nil {
+		return nil, err
+	}
+
+	tun.SetHandler(handler)
+
+	return func() {}, nil
+}
+
+func (c *CLI) configureTunnel(cmd *cobra.Command, tun *tunnel.Tunnel) error {
+	reconnect, err := cmd.PersistentFlags().GetBool("reconnect")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	retryPolicy, err := c.getRetryPolicy(cmd)
+	if err != nil {
 		return err
 	}
 
+	tun.SetReconnect(reconnect)
+	tun.SetRetryPolicy(retryPolicy)
+
 	return nil
 }
+
+func (c *CLI) getRetryPolicy(cmd *cobra.Command) (*tunnel.RetryPolicy, error) {
+	attempts, err := cmd.PersistentFlags().GetInt("retryAttempts")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	backoff, err := cmd.PersistentFlags().GetDuration("retryBackoff")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	deadline, err := cmd.PersistentFlags().GetDuration("retryDeadline")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	on, err := cmd.PersistentFlags().GetStringSlice("retryOn")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	retryPolicy := &tunnel.RetryPolicy{
+		Attempts: attempts,
+		Backoff:  backoff,
+		Deadline: deadline,
+		On:       on,
+	}
+
+	if err := retryPolicy.Validate(); err != nil {
+		return nil, errors.Errorf("Invalid retry policy: %s", err.Error())
+	}
+
+	return retryPolicy, nil
+}
@@ -0,0 +1,567 @@
+package cli_test
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/cli"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// newTunnelServer returns a tunnel server which sends given webhook requests
+// one after another, collects the responses and closes the connection afterwards
+func newTunnelServer(t *testing.T, requests []*tunnel.WebhookRequest, responses chan<- *tunnel.WebhookResponse) *httptest.Server {
+	return newTunnelServerWithClose(t, requests, responses, true)
+}
+
+// newPersistentTunnelServer returns a tunnel server like newTunnelServer, but
+// it keeps the connection open until the CLI closes it
+func newPersistentTunnelServer(t *testing.T, requests []*tunnel.WebhookRequest, responses chan<- *tunnel.WebhookResponse) *httptest.Server {
+	return newTunnelServerWithClose(t, requests, responses, false)
+}
+
+func newTunnelServerWithClose(t *testing.T, requests []*tunnel.WebhookRequest, responses chan<- *tunnel.WebhookResponse, closeConnection bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{}
+		c, err := upgrader.Upgrade(w, r, nil)
+		assert.NoError(t, err)
+		defer c.Close()
+
+		for _, request := range requests {
+			assert.NoError(t, c.WriteJSON(request))
+
+			response := &tunnel.WebhookResponse{}
+			if err := c.ReadJSON(response); err != nil {
+				break
+			}
+
+			responses <- response
+		}
+
+		close(responses)
+
+		if closeConnection {
+			_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		}
+
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func subscribe(t *testing.T, tunnelServer *httptest.Server, localAddress string, args ...string) (string, error) {
+	consoleOutput := new(bytes.Buffer)
+
+	args = append([]string{
+		"subscribe",
+		"--projectID=pro-1",
+		"--cliSecret=valid",
+		fmt.Sprintf("--tunnelAddress=ws%s", strings.TrimPrefix(tunnelServer.URL, "http")),
+		fmt.Sprintf("--historyFile=%s", filepath.Join(t.TempDir(), "history.db")),
+	}, args...)
+
+	if localAddress != "" {
+		args = append(args, localAddress)
+	}
+
+	stdout, stderr, err := cli.New(consoleOutput).ExecuteWithArgs(args...)
+	assert.Empty(t, stdout)
+	if err == nil {
+		assert.Empty(t, stderr)
+	}
+
+	return consoleOutput.String(), err
+}
+
+func TestSubscribeForwardsAndPrintsStatistics(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/webhook", r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 2)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{
+		{ID: "who-1", Path: "/webhook", Body: `{"id":"who-1"}`},
+		{ID: "who-2", Path: "/webhook", Body: `{"id":"who-2"}`},
+	}, responses)
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, localServer.URL)
+	assert.NoError(t, err)
+
+	for response := range responses {
+		assert.Equal(t, http.StatusCreated, response.Status)
+		assert.Equal(t, `{"ok":true}`, response.Body)
+	}
+
+	assert.Contains(t, output, "success!")
+	assert.Contains(t, output, "Session statistics")
+	assert.Contains(t, output, "Webhooks:   2 (timeouts: 0, errors: 0)")
+	assert.Contains(t, output, "2xx: 2")
+}
+
+func TestSubscribeReconnects(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	forwardingServer := newTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook"}}, responses)
+	defer forwardingServer.Close()
+
+	connections := 0
+	tunnelServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connections++
+		if connections > 1 {
+			forwardingServer.Config.Handler.ServeHTTP(w, r)
+
+			return
+		}
+
+		upgrader := websocket.Upgrader{}
+		c, err := upgrader.Upgrade(w, r, nil)
+		assert.NoError(t, err)
+
+		// Drop connection without close message
+		_ = c.UnderlyingConn().Close()
+	}))
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, localServer.URL, "--reconnect")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, connections)
+	assert.Equal(t, http.StatusOK, (<-responses).Status)
+	assert.Contains(t, output, "Webhooks:   1 (timeouts: 0, errors: 0)")
+}
+
+func TestSubscribeReconnectsWhileWebhookRequestInFlight(t *testing.T) {
+	dropped := make(chan struct{})
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			// Answered only after the tunnel server dropped the connection
+			<-dropped
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	forwardingServer := newTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook"}}, responses)
+	defer forwardingServer.Close()
+
+	connections := 0
+	tunnelServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connections++
+		if connections > 1 {
+			forwardingServer.Config.Handler.ServeHTTP(w, r)
+
+			return
+		}
+
+		upgrader := websocket.Upgrader{}
+		c, err := upgrader.Upgrade(w, r, nil)
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.WriteJSON(&tunnel.WebhookRequest{ID: "who-0", Path: "/slow"}))
+
+		// Drop connection without close message while the webhook request is processed
+		time.Sleep(100 * time.Millisecond)
+		_ = c.UnderlyingConn().Close()
+		time.Sleep(100 * time.Millisecond)
+		close(dropped)
+	}))
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, localServer.URL, "--reconnect")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, connections)
+	assert.Equal(t, "who-1", (<-responses).ID)
+	assert.Contains(t, output, "dropped response to webhook request who-0 (it will be sent again)")
+}
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+func TestSubscribeServesHealthAndMetrics(t *testing.T) {
+	address := freeAddress(t)
+
+	var healthResponse *http.Response
+	var metricsResponse *http.Response
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+
+		healthResponse, err = http.Get(fmt.Sprintf("http://%s/health", address))
+		assert.NoError(t, err)
+		defer healthResponse.Body.Close()
+
+		metricsResponse, err = http.Get(fmt.Sprintf("http://%s/metrics", address))
+		assert.NoError(t, err)
+		defer metricsResponse.Body.Close()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook"}}, responses)
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, localServer.URL, "--healthAddress="+address, "--metricsAddress="+address)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, (<-responses).Status)
+	assert.Equal(t, http.StatusOK, healthResponse.StatusCode)
+	assert.Equal(t, http.StatusOK, metricsResponse.StatusCode)
+	assert.Contains(t, output, "Serving health endpoint")
+}
+
+func TestSubscribeKeepsRunningWhenLocalAddressFails(t *testing.T) {
+	requests := 0
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			assert.NoError(t, err)
+			_ = conn.Close()
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 2)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{
+		{ID: "who-1", Path: "/webhook"},
+		{ID: "who-2", Path: "/webhook"},
+	}, responses)
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, localServer.URL)
+	assert.NoError(t, err)
+
+	failed := <-responses
+	assert.Equal(t, http.StatusBadGateway, failed.Status)
+	assert.Contains(t, failed.Body, "local address closed connection unexpectedly")
+	assert.NotContains(t, failed.Body, ".go:")
+	assert.Equal(t, http.StatusOK, (<-responses).Status)
+	assert.Contains(t, output, "Webhooks:   2 (timeouts: 0, errors: 1)")
+}
+
+func TestSubscribeRetriesLocalAddress(t *testing.T) {
+	requests := 0
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook"}}, responses)
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, localServer.URL, "--retryAttempts=3", "--retryBackoff=10ms")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, (<-responses).Status)
+	assert.Equal(t, 3, requests)
+	assert.Contains(t, output, "Webhooks:   1 (timeouts: 0, errors: 0)")
+}
+
+func TestSubscribeWithInvalidRetryPolicy(t *testing.T) {
+	localServer := httptest.NewServer(http.NotFoundHandler())
+	defer localServer.Close()
+
+	consoleOutput := new(bytes.Buffer)
+	_, stderr, err := cli.New(consoleOutput).ExecuteWithArgs(
+		"subscribe",
+		"--projectID=pro-1",
+		"--cliSecret=valid",
+		"--retryAttempts=2",
+		"--retryOn=sometimes",
+		localServer.URL,
+	)
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "unknown retry condition 'sometimes'")
+}
+
+func TestSubscribeHTTPSLocalAddressDefaultPort(t *testing.T) {
+	consoleOutput := new(bytes.Buffer)
+	_, stderr, err := cli.New(consoleOutput).ExecuteWithArgs(
+		"subscribe",
+		"--projectID=pro-1",
+		"--cliSecret=valid",
+		"https://127.0.0.1",
+	)
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "127.0.0.1:443 not reachable")
+}
+
+func TestSubscribeIPv6LocalAddress(t *testing.T) {
+	listener, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skip("IPv6 loopback not available")
+	}
+
+	localServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	localServer.Listener = listener
+	localServer.Start()
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook"}}, responses)
+	defer tunnelServer.Close()
+
+	_, err = subscribe(t, tunnelServer, localServer.URL)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, (<-responses).Status)
+	}
+}
+
+func TestSubscribeExecWaitsForLocalAddress(t *testing.T) {
+	consoleOutput := new(bytes.Buffer)
+	_, stderr, err := cli.New(consoleOutput).ExecuteWithArgs(
+		"subscribe",
+		"--projectID=pro-1",
+		"--cliSecret=valid",
+		"--exec=echo started",
+		"--waitTimeout=300ms",
+		"http://"+freeAddress(t),
+	)
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "not reachable (waited 300ms)")
+	assert.Contains(t, consoleOutput.String(), "[exec] started\n")
+}
+
+func TestSubscribeWaitsForLocalAddress(t *testing.T) {
+	address := freeAddress(t)
+
+	localServer := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+		ReadHeaderTimeout: time.Second,
+	}
+	defer localServer.Close()
+
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+
+		listener, err := net.Listen("tcp", address)
+		assert.NoError(t, err)
+
+		_ = localServer.Serve(listener)
+	}()
+
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook"}}, responses)
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, "http://"+address, "--wait", "--waitTimeout=5s")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, (<-responses).Status)
+	assert.Contains(t, output, "is reachable")
+}
+
+func TestSubscribeSkipsCheck(t *testing.T) {
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook"}}, responses)
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, "http://"+freeAddress(t), "--skipCheck")
+	assert.NoError(t, err)
+
+	response := <-responses
+	assert.Equal(t, http.StatusServiceUnavailable, response.Status)
+	assert.Contains(t, response.Body, "local address refused connection")
+	assert.Contains(t, output, "Webhooks:   1 (timeouts: 0, errors: 1)")
+}
+
+func TestSubscribeEcho(t *testing.T) {
+	saveDir := t.TempDir()
+
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{{
+		ID:      "who-1",
+		Path:    "/webhook",
+		Headers: map[string]string{"Authorization": "Basic secret"},
+		Body:    `{"action":"authMethods","data":{"username":"jane@example.com","password":"secret"}}`,
+	}}, responses)
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, "", "--echo", "--echoStatus=202", `--echoBody={"ok":true}`, "--echoSaveDir="+saveDir, "--redactPath=data.username")
+	assert.NoError(t, err)
+
+	response := <-responses
+	assert.Equal(t, http.StatusAccepted, response.Status)
+	assert.Equal(t, `{"ok":true}`, response.Body)
+	assert.Equal(t, "application/json", response.Headers["Content-Type"])
+
+	assert.Contains(t, output, "echo mode")
+	assert.Contains(t, output, "Authorization: [REDACTED]")
+	assert.Contains(t, output, `"action": "authMethods"`)
+	assert.NotContains(t, output, "jane@example.com")
+	assert.NotContains(t, output, "secret")
+
+	files, err := os.ReadDir(saveDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	content, err := os.ReadFile(saveDir + "/" + files[0].Name())
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"username":"[REDACTED]"`)
+	assert.Contains(t, string(content), `"password":"[REDACTED]"`)
+}
+
+func TestSubscribeEchoSaveFailureKeepsRunning(t *testing.T) {
+	saveDir := t.TempDir()
+
+	responses := make(chan *tunnel.WebhookResponse, 2)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{
+		// File name is too long to be saved
+		{ID: "who-" + strings.Repeat("1", 300), Path: "/webhook", Body: `{}`},
+		{ID: "who-2", Path: "/webhook", Body: `{}`},
+	}, responses)
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, "", "--echo", "--echoSaveDir="+saveDir)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, (<-responses).Status)
+	assert.Equal(t, http.StatusOK, (<-responses).Status)
+	assert.Contains(t, output, "Failed to save body")
+
+	files, err := os.ReadDir(saveDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestSubscribeEchoWithLocalAddress(t *testing.T) {
+	_, stderr, err := cli.New(new(bytes.Buffer)).ExecuteWithArgs("subscribe", "--echo", "http://localhost:8000")
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "There must be no argument in echo mode")
+}
+
+func TestSubscribeStopsAfterMaxRequestsAndFails(t *testing.T) {
+	requests := 0
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 2)
+	tunnelServer := newPersistentTunnelServer(t, []*tunnel.WebhookRequest{
+		{ID: "who-1", Path: "/webhook"},
+		{ID: "who-2", Path: "/webhook"},
+	}, responses)
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, localServer.URL, "--maxRequests=2", "--failOn=5xx,timeout")
+	assert.EqualError(t, err, "1 of 2 webhook requests matched failOn (5xx, timeout)")
+	assert.Contains(t, output, "Reached maximum of 2 webhook requests, stopping")
+	assert.Contains(t, output, "Webhooks:   2 (timeouts: 0, errors: 0)")
+}
+
+func TestSubscribeFailsOnStoppedLocalAddress(t *testing.T) {
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := newPersistentTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/webhook"}}, responses)
+	defer tunnelServer.Close()
+
+	_, err := subscribe(t, tunnelServer, "http://"+freeAddress(t), "--skipCheck", "--maxRequests=1", "--failOn=5xx")
+	assert.EqualError(t, err, "1 of 1 webhook requests matched failOn (5xx)")
+	assert.Equal(t, http.StatusServiceUnavailable, (<-responses).Status)
+}
+
+func TestSubscribeStopsAfterDuration(t *testing.T) {
+	localServer := httptest.NewServer(http.NotFoundHandler())
+	defer localServer.Close()
+
+	tunnelServer := newPersistentTunnelServer(t, nil, make(chan *tunnel.WebhookResponse))
+	defer tunnelServer.Close()
+
+	output, err := subscribe(t, tunnelServer, localServer.URL, "--duration=200ms", "--failOn=4xx")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Duration of 200ms elapsed, stopping")
+	assert.Contains(t, output, "Webhooks:   0")
+}
+
+func TestSubscribeWithInvalidFailOn(t *testing.T) {
+	localServer := httptest.NewServer(http.NotFoundHandler())
+	defer localServer.Close()
+
+	_, stderr, err := cli.New(new(bytes.Buffer)).ExecuteWithArgs("subscribe", "--projectID=pro-1", "--cliSecret=valid", "--failOn=7xx", localServer.URL)
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "Invalid failOn condition '7xx'")
+}
+
+func TestSubscribeTUIWithoutTerminal(t *testing.T) {
+	localServer := httptest.NewServer(http.NotFoundHandler())
+	defer localServer.Close()
+
+	_, stderr, err := cli.New(new(bytes.Buffer)).ExecuteWithArgs("subscribe", "--projectID=pro-1", "--cliSecret=valid", "--tui", localServer.URL)
+	assert.NotNil(t, err)
+	assert.Contains(t, stderr, "Dashboard not available: the dashboard needs an interactive terminal")
+}
+
+func TestSubscribeExportsSession(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 2)
+	tunnelServer := newTunnelServer(t, []*tunnel.WebhookRequest{
+		{ID: "who-1", Path: "/webhook", Headers: map[string]string{"Authorization": "Basic secret"}, Body: `{"data":{"password":"secret"}}`},
+		{ID: "who-2", Path: "/other"},
+	}, responses)
+	defer tunnelServer.Close()
+
+	exportFile := filepath.Join(t.TempDir(), "session.sh")
+
+	_, err := subscribe(t, tunnelServer, localServer.URL, "--exportFile="+exportFile, "--exportFormat=curl")
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(exportFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), fmt.Sprintf("curl -X POST '%s/webhook' -H 'Authorization: '\"$CORBADO_WEBHOOK_AUTHORIZATION\"", localServer.URL))
+	assert.Contains(t, string(content), fmt.Sprintf("curl -X POST '%s/other'", localServer.URL))
+	assert.Contains(t, string(content), `{"data":{"password":"[REDACTED]"}}`)
+	assert.NotContains(t, string(content), "secret")
+}
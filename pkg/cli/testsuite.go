@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/format"
+	"github.com/corbado/cli/pkg/testsuite"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func (c *CLI) handleTest(cmd *cobra.Command, args []string) error {
+	ansi, err := c.getAnsi()
+	if err != nil {
+		return err
+	}
+
+	suite, err := testsuite.Load(args[0])
+	if err != nil {
+		return errors.Errorf("Invalid suite %s: %s", args[0], err.Error())
+	}
+
+	localAddress := args[1]
+	vldMsg := c.validateLocalAddressURL(localAddress)
+	if vldMsg != "" {
+		return errors.Errorf("Invalid localAddress: %s", vldMsg)
+	}
+
+	junitFile, err := cmd.PersistentFlags().GetString("junit")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	cleanupTarget, err := c.prepareLocalAddress(cmd, ansi, localAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanupTarget()
+
+	// Same forwarding as for webhook requests received through the tunnel,
+	// just without the tunnel server
+	tun := tunnel.New(ansi, "")
+	tun.SetQuiet(true)
+	tun.SetLocalAddress(localAddress)
+
+	results := testsuite.Run(suite, tun, func(result *testsuite.Result) {
+		c.printTestResult(ansi, result)
+	})
+
+	if junitFile != "" {
+		if err := writeJUnitFile(junitFile, suite, results); err != nil {
+			return err
+		}
+	}
+
+	// Not a usage error from here on
+	cmd.SilenceUsage = true
+
+	failed := 0
+	for _, result := range results {
+		if !result.Passed() {
+			failed++
+		}
+	}
+
+	c.printf("\n%d passed, %d failed\n", len(results)-failed, failed)
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d tests failed", failed, len(results))
+	}
+
+	return nil
+}
+
+func (c *CLI) printTestResult(ansi *ansi.Ansi, result *testsuite.Result) {
+	if result.Passed() {
+		c.printf("%s %s (%s)\n", ansi.Bold(ansi.Green("PASS")), result.Test.Name, format.Duration(result.Duration))
+
+		return
+	}
+
+	c.printf("%s %s (%s)\n", ansi.Bold(ansi.Red("FAIL")), result.Test.Name, format.Duration(result.Duration))
+
+	if result.Error != nil {
+		c.printf("     %s\n", result.Error.Error())
+	}
+
+	for _, failure := range result.Failures {
+		c.printf("     %s\n", failure)
+	}
+}
+
+func writeJUnitFile(file string, suite *testsuite.Suite, results []*testsuite.Result) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	return testsuite.WriteJUnit(f, suite, results)
+}
@@ -0,0 +1,56 @@
+package cli_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/cli"
+)
+
+func TestTestRunsSuiteAndWritesJUnit(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/exists" {
+			_, _ = w.Write([]byte(`{"data":{"status":"exists"}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"status":"not_exists"}}`))
+	}))
+	defer localServer.Close()
+
+	dir := t.TempDir()
+	suiteFile := filepath.Join(dir, "suite.yaml")
+	assert.NoError(t, os.WriteFile(suiteFile, []byte(`
+tests:
+  - name: exists
+    request: {path: /exists, body: {action: authMethods}}
+    expect: {status: 200, json: {data.status: exists}}
+  - name: not exists
+    request: {path: /other, body: {action: authMethods}}
+    expect: {status: 200, json: {data.status: exists}}
+`), 0o600))
+
+	junitFile := filepath.Join(dir, "report.xml")
+
+	consoleOutput := new(bytes.Buffer)
+	_, _, err := cli.New(consoleOutput).ExecuteWithArgs("test", "--colors=false", "--junit", junitFile, suiteFile, localServer.URL)
+	assert.EqualError(t, err, "1 of 2 tests failed")
+
+	output := consoleOutput.String()
+	assert.Contains(t, output, "PASS exists")
+	assert.Contains(t, output, "FAIL not exists")
+	assert.Contains(t, output, "expected JSON path data.status to be exists, got not_exists")
+	assert.Contains(t, output, "1 passed, 1 failed")
+
+	report, err := os.ReadFile(junitFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(report), `tests="2" failures="1" errors="0"`)
+}
@@ -1,15 +1,21 @@
 package cli
 
 import (
-	"fmt"
+	"context"
 	"net"
+	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
-func (c *CLI) validateLocalAddress(localAddress string) string {
+var ErrInterrupted = errors.New("Interrupted")
+
+// validateLocalAddressURL validates the format of given local address without checking its reachability
+func (c *CLI) validateLocalAddressURL(localAddress string) string {
 	parsedURL, err := url.Parse(localAddress)
 	if err != nil {
 		return err.Error()
@@ -35,20 +41,108 @@ func (c *CLI) validateLocalAddress(localAddress string) string {
 		return "must have no user authentication"
 	}
 
-	port := "80"
-	if parsedURL.Port() != "" {
-		port = parsedURL.Port()
+	return ""
+}
+
+// checkLocalAddress checks if something listens on given (already validated) local address
+func (c *CLI) checkLocalAddress(localAddress string) error {
+	parsedURL, err := url.Parse(localAddress)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return checkReachable(parsedURL)
+}
+
+// waitFor calls given check until it succeeds, the timeout elapsed or given context is done
+func (c *CLI) waitFor(ctx context.Context, check func() error, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return ErrInterrupted
+			}
+
+			return errors.Errorf("%s (waited %s)", err.Error(), timeout)
+
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkURL checks if given URL answers a GET request with a 2xx status
+func (c *CLI) checkURL(checkURL string) error {
+	client := &http.Client{
+		Timeout: 3 * time.Second,
+	}
+
+	resp, err := client.Get(checkURL) //nolint:noctx
+	if err != nil {
+		return errors.Errorf("%s not reachable", checkURL)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("%s returned HTTP status %d", checkURL, resp.StatusCode)
 	}
 
-	host := fmt.Sprintf("%s:%s", parsedURL.Hostname(), port)
+	return nil
+}
 
-	if _, err := net.DialTimeout("tcp", host, time.Second*3); err != nil {
-		return fmt.Sprintf("%s not reachable", host)
+func (c *CLI) validateWaitURL(waitURL string) string {
+	parsedURL, err := url.Parse(waitURL)
+	if err != nil {
+		return err.Error()
+	}
+
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return "must have http or https scheme"
+	}
+
+	if parsedURL.Host == "" {
+		return "must have a host"
 	}
 
 	return ""
 }
 
+func checkReachable(parsedURL *url.URL) error {
+	host := buildHost(parsedURL)
+
+	conn, err := net.DialTimeout("tcp", host, time.Second*3)
+	if err != nil {
+		return errors.Errorf("%s not reachable", host)
+	}
+
+	_ = conn.Close()
+
+	return nil
+}
+
+func buildHost(parsedURL *url.URL) string {
+	port := "80"
+	if parsedURL.Scheme == "https" {
+		port = "443"
+	}
+
+	if parsedURL.Port() != "" {
+		port = parsedURL.Port()
+	}
+
+	return net.JoinHostPort(parsedURL.Hostname(), port)
+}
+
 func (c *CLI) validateProjectID(id string) bool {
 	if !strings.HasPrefix(id, "pro-") {
 		return false
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/corbado/cli/pkg/match"
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// matchedRequest is the JSON printed for the webhook request wait-for was waiting for
+type matchedRequest struct {
+	ID      string            `json:"id"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers"`
+	Body    any               `json:"body"`
+	Status  int               `json:"status"`
+}
+
+// waiter stops the tunnel as soon as a webhook request matched and the local
+// address answered it successfully
+type waiter struct {
+	tun      *tunnel.Tunnel
+	path     string
+	matchers []*match.Matcher
+
+	lock     sync.Mutex
+	matched  *tunnel.Exchange
+	stopOnce sync.Once
+}
+
+// Observe checks given exchange (implements tunnel.Observer)
+func (w *waiter) Observe(exchange *tunnel.Exchange) {
+	if exchange.Error != nil || exchange.Response == nil || exchange.Response.Status < http.StatusOK || exchange.Response.Status >= http.StatusMultipleChoices {
+		return
+	}
+
+	if w.path != "" && exchange.Request.Path != w.path {
+		return
+	}
+
+	if !match.MatchAll(w.matchers, exchange.Request) {
+		return
+	}
+
+	w.lock.Lock()
+	if w.matched == nil {
+		w.matched = exchange
+	}
+	w.lock.Unlock()
+
+	w.stop()
+}
+
+func (w *waiter) stop() {
+	w.stopOnce.Do(func() {
+		// Stop only fails if the connection is already gone
+		_ = w.tun.Stop()
+	})
+}
+
+func (w *waiter) result() *tunnel.Exchange {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.matched
+}
+
+func (c *CLI) handleWaitFor(cmd *cobra.Command, args []string) error {
+	ansi, err := c.getAnsi()
+	if err != nil {
+		return err
+	}
+
+	localAddress := args[0]
+	vldMsg := c.validateLocalAddressURL(localAddress)
+	if vldMsg != "" {
+		return errors.Errorf("Invalid localAddress: %s", vldMsg)
+	}
+
+	path, err := cmd.PersistentFlags().GetString("path")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	expressions, err := cmd.PersistentFlags().GetStringSlice("match")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	matchers, err := match.ParseAll(expressions)
+	if err != nil {
+		return errors.Errorf("Invalid match: %s", err.Error())
+	}
+
+	timeout, err := cmd.PersistentFlags().GetDuration("timeout")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if timeout <= 0 {
+		return errors.New("Invalid timeout: must be positive")
+	}
+
+	tunnelAddress, projectID, cliSecret, err := c.getTunnelCredentials(cmd)
+	if err != nil {
+		return err
+	}
+
+	redactor, err := c.getRedactor(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Only the matched webhook request goes to stdout so that it can be piped
+	// into scripts, everything else is status output
+	status := c.withOutput(cmd.ErrOrStderr())
+
+	tun := tunnel.New(ansi, tunnelAddress)
+	tun.SetQuiet(true)
+	tun.SetOutput(cmd.ErrOrStderr())
+
+	cleanupTarget, err := status.prepareLocalAddress(cmd, ansi, localAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanupTarget()
+
+	w := &waiter{
+		tun:      tun,
+		path:     path,
+		matchers: matchers,
+	}
+	tun.AddObserver(w)
+
+	connected, err := status.connectTunnel(ansi, tun, tunnelAddress, localAddress, projectID, cliSecret)
+	if err != nil || !connected {
+		return err
+	}
+
+	timer := time.AfterFunc(timeout, w.stop)
+	defer timer.Stop()
+
+	if err := tun.Start(localAddress); err != nil && err != tunnel.ErrConnectionClosed {
+		return err
+	}
+
+	// Not a usage error from here on
+	cmd.SilenceUsage = true
+
+	matched := w.result()
+	if matched == nil {
+		return errors.Errorf("No matching webhook request within %s", timeout)
+	}
+
+	return c.printMatchedRequest(redactor, matched)
+}
+
+// printMatchedRequest prints the (redacted) webhook request as single line
+// JSON so that it can be processed by scripts (e.g. with jq)
+func (c *CLI) printMatchedRequest(redactor *redact.Redactor, exchange *tunnel.Exchange) error {
+	req := exchange.Request.Redacted(redactor)
+
+	var body any = req.Body
+	if isJSON(req.Body) {
+		body = json.RawMessage(req.Body)
+	}
+
+	encoded, err := json.Marshal(&matchedRequest{
+		ID:      req.ID,
+		Path:    req.Path,
+		Headers: req.Headers,
+		Body:    body,
+		Status:  exchange.Response.Status,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	c.println(string(encoded))
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/cli"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func waitFor(t *testing.T, tunnelServer *httptest.Server, localAddress string, args ...string) (string, string, error) {
+	consoleOutput := new(bytes.Buffer)
+
+	args = append([]string{
+		"wait-for",
+		"--projectID=pro-1",
+		"--cliSecret=valid",
+		fmt.Sprintf("--tunnelAddress=ws%s", strings.TrimPrefix(tunnelServer.URL, "http")),
+	}, args...)
+	args = append(args, localAddress)
+
+	stdout, stderr, err := cli.New(consoleOutput).ExecuteWithArgs(args...)
+	if err == nil {
+		assert.Empty(t, stdout)
+	}
+
+	return consoleOutput.String(), stderr, err
+}
+
+func TestWaitForPrintsMatchingRequest(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/failing" {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 4)
+	tunnelServer := newPersistentTunnelServer(t, []*tunnel.WebhookRequest{
+		{ID: "who-1", Path: "/webhook", Body: `{"action":"userDeleted"}`},
+		{ID: "who-2", Path: "/failing", Body: `{"action":"userCreated"}`},
+		{ID: "who-3", Path: "/webhook", Headers: map[string]string{"Authorization": "Basic secret"}, Body: `{"action":"userCreated","data":{"username":"jane","password":"secret"}}`},
+		{ID: "who-4", Path: "/webhook", Body: `{"action":"userCreated"}`},
+	}, responses)
+	defer tunnelServer.Close()
+
+	output, stderr, err := waitFor(t, tunnelServer, localServer.URL, "--path=/webhook", "--match=body.action=userCreated", "--redactPath=data.username")
+	assert.NoError(t, err)
+
+	// Nothing but the matched webhook request so that the output can be piped into jq
+	assert.Equal(t, `{"id":"who-3","path":"/webhook","headers":{"Authorization":"[REDACTED]"},"body":{"action":"userCreated","data":{"password":"[REDACTED]","username":"[REDACTED]"}},"status":200}`+"\n", output)
+	assert.True(t, json.Valid([]byte(output)))
+	assert.Contains(t, stderr, "Subscribing to tunnel server")
+}
+
+func TestWaitForTimesOut(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer localServer.Close()
+
+	responses := make(chan *tunnel.WebhookResponse, 1)
+	tunnelServer := newPersistentTunnelServer(t, []*tunnel.WebhookRequest{{ID: "who-1", Path: "/other"}}, responses)
+	defer tunnelServer.Close()
+
+	_, _, err := waitFor(t, tunnelServer, localServer.URL, "--path=/webhook", "--timeout=300ms")
+	assert.EqualError(t, err, "No matching webhook request within 300ms")
+}
+
+func TestWaitForWithInvalidMatch(t *testing.T) {
+	tunnelServer := newPersistentTunnelServer(t, nil, make(chan *tunnel.WebhookResponse))
+	defer tunnelServer.Close()
+
+	_, _, err := waitFor(t, tunnelServer, "http://localhost:1", "--match=unknown")
+	assert.ErrorContains(t, err, "Invalid match")
+}
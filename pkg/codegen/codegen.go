@@ -0,0 +1,85 @@
+package codegen
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/schema"
+)
+
+const (
+	LangTypeScript = "ts"
+	LangGo         = "go"
+	LangPython     = "python"
+)
+
+// Options defines how code is generated
+type Options struct {
+	// Package is the package clause of generated Go code
+	Package string
+}
+
+// ValidateLang checks if code can be generated for given language
+func ValidateLang(lang string) error {
+	switch lang {
+	case LangTypeScript, LangGo, LangPython:
+		return nil
+	}
+
+	return errors.Errorf("unsupported language '%s' (allowed: ts, go, python)", lang)
+}
+
+// Generate returns type definitions for the webhook requests and responses of
+// given actions and a handler skeleton dispatching by action
+func Generate(lang string, actions []*schema.Action, options *Options) ([]byte, error) {
+	if err := ValidateLang(lang); err != nil {
+		return nil, err
+	}
+
+	m, err := newModel(actions)
+	if err != nil {
+		return nil, err
+	}
+
+	switch lang {
+	case LangGo:
+		return generateGo(m, options)
+
+	case LangPython:
+		return []byte(generatePython(m)), nil
+
+	default:
+		return []byte(generateTypeScript(m)), nil
+	}
+}
+
+const header = "Generated by corbado codegen from the webhook schema catalog: regenerate the types, implement the handlers."
+
+// writer collects generated lines
+type writer struct {
+	builder strings.Builder
+}
+
+func (w *writer) line(parts ...string) {
+	for _, part := range parts {
+		w.builder.WriteString(part)
+	}
+
+	w.builder.WriteByte('\n')
+}
+
+func (w *writer) String() string {
+	return w.builder.String()
+}
+
+// comment writes given text as comment lines with given prefix (e.g. "// ")
+func (w *writer) comment(indent string, prefix string, text string) {
+	if text == "" {
+		return
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		w.line(indent, prefix, line)
+	}
+}
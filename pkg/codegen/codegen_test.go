@@ -0,0 +1,117 @@
+package codegen_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/codegen"
+	"github.com/corbado/cli/pkg/schema"
+)
+
+func generate(t *testing.T, lang string) string {
+	actions, err := schema.Actions()
+	assert.NoError(t, err)
+
+	source, err := codegen.Generate(lang, actions, &codegen.Options{Package: "webhooks"})
+	assert.NoError(t, err)
+
+	return string(source)
+}
+
+func TestTypeScript(t *testing.T) {
+	source := generate(t, codegen.LangTypeScript)
+
+	assert.Contains(t, source, "export interface AuthMethodsRequest {\n  // ID of the webhook request, to be returned as responseID\n  id: string;\n")
+	assert.Contains(t, source, `  action: "authMethods";`)
+	assert.Contains(t, source, `  status: "exists" | "not_exists" | "blocked";`)
+	assert.Contains(t, source, "export type WebhookRequest = AuthMethodsRequest | PasswordVerifyRequest;")
+	assert.Contains(t, source, "export async function handlePasswordVerify(request: PasswordVerifyRequest): Promise<PasswordVerifyResponse> {")
+	assert.Contains(t, source, "    responseID: request.id,\n    data: {\n      success: false,\n    },\n")
+}
+
+func TestPython(t *testing.T) {
+	source := generate(t, codegen.LangPython)
+
+	assert.Contains(t, source, "from typing import Literal, TypedDict, Union, cast\n")
+	assert.Contains(t, source, "class AuthMethodsResponseData(TypedDict):\n    # Status of the user\n    status: Literal[\"exists\", \"not_exists\", \"blocked\"]\n")
+	assert.Contains(t, source, "def handle_password_verify(request: PasswordVerifyRequest) -> PasswordVerifyResponse:")
+
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		return
+	}
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "webhooks.py"), []byte(source), 0o600))
+
+	cmd := exec.Command(python, "-c", `import webhooks; print(webhooks.handle_webhook({"id": "who-1", "projectID": "pro-1", "action": "authMethods", "data": {"username": "jane"}}))`)
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(output))
+	assert.Equal(t, "{'responseID': 'who-1', 'data': {'status': 'exists'}}\n", string(output))
+}
+
+func TestGo(t *testing.T) {
+	source := generate(t, codegen.LangGo)
+
+	assert.Contains(t, source, "package webhooks\n")
+	assert.Contains(t, source, "// AuthMethodsRequest is sent by Corbado to find out if a user exists in your system\ntype AuthMethodsRequest struct {")
+	assert.Contains(t, source, "// AuthMethodsResponse tells Corbado if the user exists in your system\ntype AuthMethodsResponse struct {")
+	assert.Contains(t, source, "AuthMethodsResponseDataStatusNotExists AuthMethodsResponseDataStatus = \"not_exists\"")
+	assert.Contains(t, source, "func HandleAuthMethods(ctx context.Context, req *AuthMethodsRequest) (*AuthMethodsResponse, error) {")
+	assert.Contains(t, source, "func NewHandler() http.Handler {")
+}
+
+// TestGoRuns compiles the generated Go code and sends a webhook request to the
+// generated handler
+func TestGoRuns(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+
+	goBinary, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found")
+	}
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/webhooks\n\ngo 1.19\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "webhooks.go"), []byte(generate(t, codegen.LangGo)), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "webhooks_test.go"), []byte(`package webhooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	body := `+"`"+`{"id":"who-1","projectID":"pro-1","action":"passwordVerify","data":{"username":"jane","password":"secret"}}`+"`"+`
+
+	rec := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body)))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != `+"`"+`{"responseID":"who-1","data":{"success":false}}`+"`"+`+"\n" {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+}
+`), 0o600))
+
+	cmd := exec.Command(goBinary, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(output))
+}
+
+func TestGenerateWithInvalidLang(t *testing.T) {
+	_, err := codegen.Generate("rust", nil, &codegen.Options{})
+	assert.EqualError(t, err, "unsupported language 'rust' (allowed: ts, go, python)")
+}
@@ -0,0 +1,232 @@
+package codegen
+
+import (
+	"go/format"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/schema"
+)
+
+func generateGo(m *model, options *Options) ([]byte, error) {
+	w := &writer{}
+	w.line("// ", header)
+	w.line()
+	w.line("package ", options.Package)
+	w.line()
+	w.line("import (")
+	w.line(`"context"`)
+	w.line(`"encoding/json"`)
+	w.line(`"io"`)
+	w.line(`"net/http"`)
+	w.line(")")
+
+	for _, object := range m.Objects {
+		w.line()
+		w.comment("", "// ", goDescription(object.Name, object.Description))
+		w.line("type ", object.Name, " struct {")
+
+		for _, f := range object.Fields {
+			w.comment("", "// ", f.Description)
+
+			typ := goType(f.Type)
+			tag := f.Name
+			if !f.Required {
+				typ = "*" + typ
+				tag += ",omitempty"
+			}
+
+			w.line(goName(f.Name), " ", typ, " `json:\"", tag, "\"`")
+		}
+
+		w.line("}")
+	}
+
+	for _, enum := range m.Enums {
+		w.line()
+		w.line("type ", enum.Name, " string")
+		w.line()
+		w.line("const (")
+
+		for _, value := range enum.Values {
+			w.line(enum.Name+pascal(value), " ", enum.Name, " = ", strconv.Quote(value))
+		}
+
+		w.line(")")
+	}
+
+	writeGoHandler(w, m)
+
+	source, err := format.Source([]byte(w.String()))
+	if err != nil {
+		return nil, errors.Errorf("invalid generated code: %s", err.Error())
+	}
+
+	return source, nil
+}
+
+func writeGoHandler(w *writer, m *model) {
+	w.line()
+	w.line("// NewHandler returns an http.Handler dispatching webhook requests to the handler of their action")
+	w.line("func NewHandler() http.Handler {")
+	w.line("return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {")
+	w.line("body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))")
+	w.line("if err != nil {")
+	w.line("http.Error(w, err.Error(), http.StatusBadRequest)")
+	w.line()
+	w.line("return")
+	w.line("}")
+	w.line()
+	w.line("var envelope struct {")
+	w.line("Action string `json:\"action\"`")
+	w.line("}")
+	w.line()
+	w.line("if err := json.Unmarshal(body, &envelope); err != nil {")
+	w.line("http.Error(w, err.Error(), http.StatusBadRequest)")
+	w.line()
+	w.line("return")
+	w.line("}")
+	w.line()
+	w.line("var response any")
+	w.line()
+	w.line("switch envelope.Action {")
+
+	for _, a := range m.Actions {
+		w.line("case ", strconv.Quote(a.Name), ":")
+		w.line("req := &", a.Request.Name, "{}")
+		w.line("if err := json.Unmarshal(body, req); err != nil {")
+		w.line("http.Error(w, err.Error(), http.StatusBadRequest)")
+		w.line()
+		w.line("return")
+		w.line("}")
+		w.line()
+		w.line("response, err = Handle", pascal(a.Name), "(r.Context(), req)")
+		w.line()
+	}
+
+	w.line("default:")
+	w.line(`http.Error(w, "unknown webhook action "+envelope.Action, http.StatusBadRequest)`)
+	w.line()
+	w.line("return")
+	w.line("}")
+	w.line()
+	w.line("if err != nil {")
+	w.line("http.Error(w, err.Error(), http.StatusInternalServerError)")
+	w.line()
+	w.line("return")
+	w.line("}")
+	w.line()
+	w.line(`w.Header().Set("Content-Type", "application/json")`)
+	w.line("_ = json.NewEncoder(w).Encode(response)")
+	w.line("})")
+	w.line("}")
+
+	for _, a := range m.Actions {
+		w.line()
+		w.line("// Handle", pascal(a.Name), " answers ", a.Name, " webhook requests")
+		w.line("func Handle", pascal(a.Name), "(ctx context.Context, req *", a.Request.Name, ") (*", a.Response.Name, ", error) {")
+		w.line("// TODO: implement")
+		w.line("return &", goValue(m, a.Response), ", nil")
+		w.line("}")
+	}
+}
+
+// goDescription turns a description into a Go doc comment starting with the
+// type name, descriptions not starting with a verb (e.g. "Sent by Corbado")
+// are joined with "is"
+func goDescription(name string, description string) string {
+	if description == "" {
+		return ""
+	}
+
+	description = strings.ToLower(description[:1]) + description[1:]
+
+	firstWord := strings.SplitN(description, " ", 2)[0]
+	if !strings.HasSuffix(firstWord, "s") {
+		return name + " is " + description
+	}
+
+	return name + " " + description
+}
+
+func goType(ref *typeRef) string {
+	switch ref.Kind {
+	case schema.TypeObject:
+		if ref.Name == "" {
+			return "map[string]any"
+		}
+
+		return ref.Name
+
+	case schema.TypeArray:
+		return "[]" + goType(ref.Items)
+
+	case schema.TypeString:
+		return "string"
+
+	case "enum":
+		if ref.Name == "" {
+			return "string"
+		}
+
+		return ref.Name
+
+	case schema.TypeBoolean:
+		return "bool"
+
+	case schema.TypeInteger:
+		return "int64"
+
+	case schema.TypeNumber:
+		return "float64"
+
+	default:
+		return "any"
+	}
+}
+
+// goName returns the exported field name of a JSON property (e.g. ID for id)
+func goName(name string) string {
+	switch strings.ToLower(name) {
+	case "id", "url", "uri", "api", "http", "json":
+		return strings.ToUpper(name)
+	}
+
+	return pascal(name)
+}
+
+// goValue returns the composite literal of the default response (required fields only)
+func goValue(m *model, object *objectType) string {
+	fields := make([]string, 0, len(object.Fields))
+
+	for _, f := range object.Fields {
+		if !f.Required {
+			continue
+		}
+
+		var value string
+
+		switch {
+		case f.Name == "responseID":
+			value = "req.ID"
+
+		case f.Type.Kind == schema.TypeObject && f.Type.Name != "":
+			value = goValue(m, m.objects[f.Type.Name])
+
+		case f.Type.Kind == "enum" && f.Type.Name != "":
+			value = f.Type.Name + pascal(f.Type.Values[0])
+
+		case f.Type.Kind == schema.TypeArray || f.Type.Kind == schema.TypeObject:
+			value = "nil"
+
+		default:
+			value = literal(f.Type, "nil", "false", "nil")
+		}
+
+		fields = append(fields, goName(f.Name)+": "+value+",\n")
+	}
+
+	return object.Name + "{\n" + strings.Join(fields, "") + "}"
+}
@@ -0,0 +1,231 @@
+package codegen
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/schema"
+)
+
+// typeRef is the type of a field, kind is a JSON Schema type, "union" for
+// multiple types or "enum" for enumerations of strings
+type typeRef struct {
+	Kind   string
+	Name   string
+	Items  *typeRef
+	Types  []string
+	Values []string
+}
+
+type field struct {
+	Name        string
+	Description string
+	Type        *typeRef
+	Required    bool
+}
+
+type objectType struct {
+	Name        string
+	Description string
+	Fields      []*field
+}
+
+type enumType struct {
+	Name   string
+	Values []string
+}
+
+type action struct {
+	Name     string
+	Request  *objectType
+	Response *objectType
+}
+
+// model holds all types of the catalog in the order they are defined
+type model struct {
+	Objects []*objectType
+	Enums   []*enumType
+	Actions []*action
+
+	objects map[string]*objectType
+}
+
+func newModel(actions []*schema.Action) (*model, error) {
+	m := &model{objects: map[string]*objectType{}}
+
+	for _, a := range actions {
+		request, err := m.addObject(titleOr(a.Request, pascal(a.Name)+"Request"), a.Request)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := m.addObject(titleOr(a.Response, pascal(a.Name)+"Response"), a.Response)
+		if err != nil {
+			return nil, err
+		}
+
+		m.Actions = append(m.Actions, &action{Name: a.Name, Request: request, Response: response})
+	}
+
+	return m, nil
+}
+
+func titleOr(s *schema.Schema, name string) string {
+	if s.Title != "" {
+		return s.Title
+	}
+
+	return name
+}
+
+func (m *model) addObject(name string, s *schema.Schema) (*objectType, error) {
+	if _, ok := m.objects[name]; ok {
+		return nil, errors.Errorf("type %s is defined twice", name)
+	}
+
+	object := &objectType{Name: name, Description: s.Description}
+	m.objects[name] = object
+	m.Objects = append(m.Objects, object)
+
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+
+	// Required fields in order of the schema's required list, optional ones by name
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	sort.SliceStable(keys, func(i, j int) bool { return requiredIndex(s, keys[i]) < requiredIndex(s, keys[j]) })
+
+	for _, key := range keys {
+		property := s.Properties[key]
+
+		ref, err := m.typeRef(name+pascal(key), property)
+		if err != nil {
+			return nil, err
+		}
+
+		object.Fields = append(object.Fields, &field{
+			Name:        key,
+			Description: property.Description,
+			Type:        ref,
+			Required:    requiredIndex(s, key) < len(s.Required),
+		})
+	}
+
+	return object, nil
+}
+
+func requiredIndex(s *schema.Schema, key string) int {
+	for i, required := range s.Required {
+		if required == key {
+			return i
+		}
+	}
+
+	return len(s.Required)
+}
+
+func (m *model) typeRef(name string, s *schema.Schema) (*typeRef, error) {
+	if len(s.Type) != 1 {
+		return &typeRef{Kind: "union", Types: s.Type}, nil
+	}
+
+	switch s.Type[0] {
+	case schema.TypeObject:
+		if len(s.Properties) == 0 {
+			return &typeRef{Kind: schema.TypeObject}, nil
+		}
+
+		object, err := m.addObject(name, s)
+		if err != nil {
+			return nil, err
+		}
+
+		return &typeRef{Kind: schema.TypeObject, Name: object.Name}, nil
+
+	case schema.TypeArray:
+		items := &typeRef{Kind: "union"}
+		if s.Items != nil {
+			var err error
+			if items, err = m.typeRef(name+"Item", s.Items); err != nil {
+				return nil, err
+			}
+		}
+
+		return &typeRef{Kind: schema.TypeArray, Items: items}, nil
+
+	case schema.TypeString:
+		if len(s.Enum) == 0 {
+			return &typeRef{Kind: schema.TypeString}, nil
+		}
+
+		values := make([]string, 0, len(s.Enum))
+		for _, value := range s.Enum {
+			str, ok := value.(string)
+			if !ok {
+				return nil, errors.Errorf("enum of %s must only contain strings", name)
+			}
+
+			values = append(values, str)
+		}
+
+		ref := &typeRef{Kind: "enum", Values: values}
+
+		// Single values (e.g. the action) are constants, not enumerations
+		if len(values) > 1 {
+			ref.Name = name
+			m.Enums = append(m.Enums, &enumType{Name: name, Values: values})
+		}
+
+		return ref, nil
+
+	default:
+		return &typeRef{Kind: s.Type[0]}, nil
+	}
+}
+
+// pascal converts camelCase or snake_case to PascalCase (e.g. authMethods to
+// AuthMethods, not_exists to NotExists)
+func pascal(s string) string {
+	var result strings.Builder
+
+	upper := true
+	for _, r := range s {
+		if r == '_' || r == '-' || r == ' ' || r == '.' {
+			upper = true
+
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+
+		result.WriteRune(r)
+	}
+
+	return result.String()
+}
+
+// snake converts camelCase to snake_case (e.g. authMethods to auth_methods)
+func snake(s string) string {
+	var result strings.Builder
+
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				result.WriteByte('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		result.WriteRune(r)
+	}
+
+	return result.String()
+}
@@ -0,0 +1,209 @@
+package codegen
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/corbado/cli/pkg/schema"
+)
+
+const pythonIndent = "    "
+
+func generatePython(m *model) string {
+	imports := map[string]bool{"TypedDict": true, "Union": true, "cast": true}
+
+	body := &writer{}
+
+	for _, object := range m.Objects {
+		required := make([]*field, 0, len(object.Fields))
+		optional := make([]*field, 0, len(object.Fields))
+
+		for _, f := range object.Fields {
+			if f.Required {
+				required = append(required, f)
+			} else {
+				optional = append(optional, f)
+			}
+		}
+
+		base := "TypedDict"
+
+		// Optional keys need a base class with total=False
+		if len(optional) > 0 {
+			base = "_" + object.Name + "Optional"
+
+			body.line()
+			body.line()
+			body.line("class ", base, "(TypedDict, total=False):")
+			writePythonFields(body, optional, imports)
+		}
+
+		body.line()
+		body.line()
+		body.line("class ", object.Name, "(", base, "):")
+
+		if object.Description != "" {
+			body.line(pythonIndent, `"""`, object.Description, `"""`)
+			body.line()
+		}
+
+		if len(required) == 0 {
+			body.line(pythonIndent, "pass")
+		}
+
+		writePythonFields(body, required, imports)
+	}
+
+	writePythonHandler(body, m)
+
+	names := make([]string, 0, len(imports))
+	for name := range imports {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	w := &writer{}
+	w.line("# ", header)
+	w.line()
+	w.line("from __future__ import annotations")
+	w.line()
+	w.line("from typing import ", strings.Join(names, ", "))
+	w.builder.WriteString(body.String())
+
+	return w.String()
+}
+
+func writePythonFields(w *writer, fields []*field, imports map[string]bool) {
+	for _, f := range fields {
+		w.comment(pythonIndent, "# ", f.Description)
+		w.line(pythonIndent, f.Name, ": ", pythonType(f.Type, imports))
+	}
+}
+
+func writePythonHandler(w *writer, m *model) {
+	requests := make([]string, 0, len(m.Actions))
+	responses := make([]string, 0, len(m.Actions))
+
+	for _, a := range m.Actions {
+		requests = append(requests, a.Request.Name)
+		responses = append(responses, a.Response.Name)
+	}
+
+	w.line()
+	w.line()
+	w.line("WebhookRequest = Union[", strings.Join(requests, ", "), "]")
+	w.line("WebhookResponse = Union[", strings.Join(responses, ", "), "]")
+	w.line()
+	w.line()
+	w.line("def handle_webhook(request: WebhookRequest) -> WebhookResponse:")
+	w.line(pythonIndent, `"""Dispatches a webhook request to the handler of its action"""`)
+	w.line(pythonIndent, `action = request["action"]`)
+
+	for _, a := range m.Actions {
+		w.line(pythonIndent, "if action == ", strconv.Quote(a.Name), ":")
+		w.line(pythonIndent, pythonIndent, "return handle_", snake(a.Name), "(cast(", a.Request.Name, ", request))")
+	}
+
+	w.line()
+	w.line(pythonIndent, `raise ValueError(f"unknown webhook action {action}")`)
+
+	for _, a := range m.Actions {
+		w.line()
+		w.line()
+		w.line("def handle_", snake(a.Name), "(request: ", a.Request.Name, ") -> ", a.Response.Name, ":")
+		w.line(pythonIndent, "# TODO: implement")
+		w.line(pythonIndent, "return ", pythonValue(m, a.Response, pythonIndent))
+	}
+}
+
+func pythonType(ref *typeRef, imports map[string]bool) string {
+	switch ref.Kind {
+	case schema.TypeObject:
+		if ref.Name == "" {
+			imports["Any"] = true
+			imports["Dict"] = true
+
+			return "Dict[str, Any]"
+		}
+
+		return ref.Name
+
+	case schema.TypeArray:
+		imports["List"] = true
+
+		return "List[" + pythonType(ref.Items, imports) + "]"
+
+	case schema.TypeString:
+		return "str"
+
+	case "enum":
+		imports["Literal"] = true
+
+		values := make([]string, 0, len(ref.Values))
+		for _, value := range ref.Values {
+			values = append(values, strconv.Quote(value))
+		}
+
+		return "Literal[" + strings.Join(values, ", ") + "]"
+
+	case schema.TypeBoolean:
+		return "bool"
+
+	case schema.TypeInteger:
+		return "int"
+
+	case schema.TypeNumber:
+		return "float"
+
+	case schema.TypeNull:
+		return "None"
+
+	default:
+		if len(ref.Types) == 0 {
+			imports["Any"] = true
+
+			return "Any"
+		}
+
+		types := make([]string, 0, len(ref.Types))
+		for _, typ := range ref.Types {
+			types = append(types, pythonType(&typeRef{Kind: typ}, imports))
+		}
+
+		return "Union[" + strings.Join(types, ", ") + "]"
+	}
+}
+
+// pythonValue returns the dict literal of the default response (required keys only)
+func pythonValue(m *model, object *objectType, indent string) string {
+	fields := make([]string, 0, len(object.Fields))
+
+	for _, f := range object.Fields {
+		if !f.Required {
+			continue
+		}
+
+		var value string
+
+		switch {
+		case f.Name == "responseID":
+			value = `request["id"]`
+
+		case f.Type.Kind == schema.TypeObject && f.Type.Name != "":
+			value = pythonValue(m, m.objects[f.Type.Name], indent+pythonIndent)
+
+		default:
+			value = literal(f.Type, "None", "False", "{}")
+		}
+
+		fields = append(fields, indent+pythonIndent+strconv.Quote(f.Name)+": "+value)
+	}
+
+	if len(fields) == 0 {
+		return "{}"
+	}
+
+	return "{\n" + strings.Join(fields, ",\n") + ",\n" + indent + "}"
+}
@@ -0,0 +1,166 @@
+package codegen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/corbado/cli/pkg/schema"
+)
+
+func generateTypeScript(m *model) string {
+	w := &writer{}
+	w.line("// ", header)
+
+	for _, object := range m.Objects {
+		w.line()
+		w.comment("", "// ", object.Description)
+		w.line("export interface ", object.Name, " {")
+
+		for _, f := range object.Fields {
+			w.comment("  ", "// ", f.Description)
+
+			optional := ""
+			if !f.Required {
+				optional = "?"
+			}
+
+			w.line("  ", f.Name, optional, ": ", tsType(f.Type), ";")
+		}
+
+		w.line("}")
+	}
+
+	requests := make([]string, 0, len(m.Actions))
+	responses := make([]string, 0, len(m.Actions))
+
+	for _, a := range m.Actions {
+		requests = append(requests, a.Request.Name)
+		responses = append(responses, a.Response.Name)
+	}
+
+	w.line()
+	w.line("export type WebhookRequest = ", strings.Join(requests, " | "), ";")
+	w.line()
+	w.line("export type WebhookResponse = ", strings.Join(responses, " | "), ";")
+	w.line()
+	w.line("// handleWebhook dispatches a webhook request to the handler of its action")
+	w.line("export async function handleWebhook(request: WebhookRequest): Promise<WebhookResponse> {")
+	w.line("  switch (request.action) {")
+
+	for _, a := range m.Actions {
+		w.line("    case ", strconv.Quote(a.Name), ":")
+		w.line("      return handle", pascal(a.Name), "(request);")
+	}
+
+	w.line("  }")
+	w.line("}")
+
+	for _, a := range m.Actions {
+		w.line()
+		w.line("export async function handle", pascal(a.Name), "(request: ", a.Request.Name, "): Promise<", a.Response.Name, "> {")
+		w.line("  // TODO: implement")
+		w.line("  return ", tsValue(m, a.Response, ""), ";")
+		w.line("}")
+	}
+
+	return w.String()
+}
+
+func tsType(ref *typeRef) string {
+	switch ref.Kind {
+	case schema.TypeObject:
+		if ref.Name == "" {
+			return "Record<string, unknown>"
+		}
+
+		return ref.Name
+
+	case schema.TypeArray:
+		return tsType(ref.Items) + "[]"
+
+	case schema.TypeInteger, schema.TypeNumber:
+		return "number"
+
+	case "enum":
+		values := make([]string, 0, len(ref.Values))
+		for _, value := range ref.Values {
+			values = append(values, strconv.Quote(value))
+		}
+
+		return strings.Join(values, " | ")
+
+	case "union":
+		if len(ref.Types) == 0 {
+			return "unknown"
+		}
+
+		types := make([]string, 0, len(ref.Types))
+		for _, typ := range ref.Types {
+			types = append(types, tsType(&typeRef{Kind: typ}))
+		}
+
+		return strings.Join(types, " | ")
+
+	default:
+		return ref.Kind
+	}
+}
+
+// tsValue returns the literal of the default response (required fields only)
+func tsValue(m *model, object *objectType, indent string) string {
+	fields := make([]string, 0, len(object.Fields))
+
+	for _, f := range object.Fields {
+		if !f.Required {
+			continue
+		}
+
+		var value string
+
+		switch {
+		case f.Name == "responseID":
+			value = "request.id"
+
+		case f.Type.Kind == schema.TypeObject && f.Type.Name != "":
+			value = tsValue(m, m.objects[f.Type.Name], indent+"  ")
+
+		default:
+			value = literal(f.Type, "null", "false", "{}")
+		}
+
+		fields = append(fields, indent+"    "+f.Name+": "+value)
+	}
+
+	if len(fields) == 0 {
+		return "{}"
+	}
+
+	return "{\n" + strings.Join(fields, ",\n") + ",\n" + indent + "  }"
+}
+
+// literal returns the default literal of a scalar, array or free-form object
+// type in C-like syntax with given null, false and empty object literals
+func literal(ref *typeRef, null string, falseLiteral string, emptyObject string) string {
+	switch ref.Kind {
+	case schema.TypeString:
+		return `""`
+
+	case "enum":
+		return strconv.Quote(ref.Values[0])
+
+	case schema.TypeBoolean:
+		return falseLiteral
+
+	case schema.TypeNumber, schema.TypeInteger:
+		return "0"
+
+	case schema.TypeArray:
+		return "[]"
+
+	case schema.TypeObject:
+		return emptyObject
+
+	default:
+		return null
+	}
+}
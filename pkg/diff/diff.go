@@ -0,0 +1,253 @@
+package diff
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/jsonpath"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+type Kind string
+
+const (
+	KindAdded   Kind = "+"
+	KindRemoved Kind = "-"
+	KindChanged Kind = "~"
+)
+
+// Change is a single difference at a dot separated path (e.g. body.data.status)
+type Change struct {
+	Kind Kind
+	Path string
+	Old  any
+	New  any
+}
+
+// Rule ignores all changes at (and below) paths it matches. Rules are dot
+// separated paths of object keys and array indices, * matches every key and
+// index (e.g. headers.Date or body.data.*.createdAt, like --redactPath)
+type Rule []string
+
+// DefaultIgnore returns the rules applied if none are configured (Content-Length
+// changes with every body change, which is reported already)
+func DefaultIgnore() []string {
+	return []string{"headers.Date", "headers.Content-Length"}
+}
+
+// ParseRules parses given ignore rules
+func ParseRules(values []string) ([]Rule, error) {
+	rules := make([]Rule, 0, len(values))
+
+	for _, value := range values {
+		if value == "" {
+			return nil, errors.New("ignore rule must not be empty")
+		}
+
+		rule := Rule(strings.Split(value, "."))
+		for _, segment := range rule {
+			if segment == "" {
+				return nil, errors.Errorf("invalid ignore rule '%s'", value)
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func (r Rule) match(segments []string) bool {
+	if len(segments) < len(r) {
+		return false
+	}
+
+	for i, pattern := range r {
+		if !jsonpath.MatchSegment(pattern, segments[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Document returns the comparable representation of an exchange: status,
+// headers, body (decoded if JSON) and error
+func Document(exchange *tunnel.Exchange) map[string]any {
+	document := map[string]any{}
+
+	if exchange.Response != nil {
+		headers := map[string]any{}
+		for name, value := range exchange.Response.Headers {
+			headers[name] = value
+		}
+
+		document["status"] = json.Number(strconv.Itoa(exchange.Response.Status))
+		document["headers"] = headers
+		document["body"] = body(exchange.Response.Body)
+	}
+
+	if exchange.Error != nil {
+		document["error"] = exchange.Error.Error()
+	}
+
+	return document
+}
+
+func body(value string) any {
+	if strings.TrimSpace(value) == "" {
+		return value
+	}
+
+	data, err := jsonpath.Parse(value)
+	if err != nil {
+		return value
+	}
+
+	return data
+}
+
+// Exchanges compares the responses of two exchanges
+func Exchanges(a *tunnel.Exchange, b *tunnel.Exchange, ignore []Rule) []Change {
+	return Compare(Document(a), Document(b), ignore)
+}
+
+// Compare returns the structural differences between two decoded JSON values,
+// ordered by path
+func Compare(a any, b any, ignore []Rule) []Change {
+	var changes []Change
+
+	compare(nil, a, b, ignore, &changes)
+
+	return changes
+}
+
+func compare(segments []string, a any, b any, ignore []Rule, changes *[]Change) {
+	for _, rule := range ignore {
+		if rule.match(segments) {
+			return
+		}
+	}
+
+	switch aValue := a.(type) {
+	case map[string]any:
+		if bValue, ok := b.(map[string]any); ok {
+			compareObjects(segments, aValue, bValue, ignore, changes)
+
+			return
+		}
+
+	case []any:
+		if bValue, ok := b.([]any); ok {
+			compareArrays(segments, aValue, bValue, ignore, changes)
+
+			return
+		}
+	}
+
+	if !equal(a, b) {
+		*changes = append(*changes, Change{Kind: KindChanged, Path: strings.Join(segments, "."), Old: a, New: b})
+	}
+}
+
+func compareObjects(segments []string, a map[string]any, b map[string]any, ignore []Rule, changes *[]Change) {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		aValue, aOK := a[key]
+		bValue, bOK := b[key]
+
+		compareMember(appendSegment(segments, key), aValue, aOK, bValue, bOK, ignore, changes)
+	}
+}
+
+func compareArrays(segments []string, a []any, b []any, ignore []Rule, changes *[]Change) {
+	length := len(a)
+	if len(b) > length {
+		length = len(b)
+	}
+
+	for i := 0; i < length; i++ {
+		var aValue, bValue any
+		if i < len(a) {
+			aValue = a[i]
+		}
+
+		if i < len(b) {
+			bValue = b[i]
+		}
+
+		compareMember(appendSegment(segments, strconv.Itoa(i)), aValue, i < len(a), bValue, i < len(b), ignore, changes)
+	}
+}
+
+func compareMember(segments []string, a any, aOK bool, b any, bOK bool, ignore []Rule, changes *[]Change) {
+	if aOK && bOK {
+		compare(segments, a, b, ignore, changes)
+
+		return
+	}
+
+	for _, rule := range ignore {
+		if rule.match(segments) {
+			return
+		}
+	}
+
+	if aOK {
+		*changes = append(*changes, Change{Kind: KindRemoved, Path: strings.Join(segments, "."), Old: a})
+	} else {
+		*changes = append(*changes, Change{Kind: KindAdded, Path: strings.Join(segments, "."), New: b})
+	}
+}
+
+func appendSegment(segments []string, segment string) []string {
+	result := make([]string, len(segments), len(segments)+1)
+	copy(result, segments)
+
+	return append(result, segment)
+}
+
+// equal compares scalars, numbers by value so that 1.0 equals 1
+func equal(a any, b any) bool {
+	aNumber, aOK := a.(json.Number)
+	bNumber, bOK := b.(json.Number)
+
+	if aOK && bOK {
+		if aNumber == bNumber {
+			return true
+		}
+
+		aFloat, aErr := aNumber.Float64()
+		bFloat, bErr := bNumber.Float64()
+
+		return aErr == nil && bErr == nil && aFloat == bFloat
+	}
+
+	return a == b
+}
+
+// Value returns given value JSON encoded for display
+func Value(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(encoded)
+}
@@ -0,0 +1,88 @@
+package diff_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/diff"
+	"github.com/corbado/cli/pkg/jsonpath"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func parse(t *testing.T, body string) any {
+	data, err := jsonpath.Parse(body)
+	assert.NoError(t, err)
+
+	return data
+}
+
+func TestCompare(t *testing.T) {
+	a := parse(t, `{"data":{"status":"exists","count":1.0,"tags":["a","b"],"old":true}}`)
+	b := parse(t, `{"data":{"status":"not_exists","count":1,"tags":["a"],"new":null}}`)
+
+	changes := diff.Compare(a, b, nil)
+	assert.Equal(t, []diff.Change{
+		{Kind: diff.KindAdded, Path: "data.new", New: nil},
+		{Kind: diff.KindRemoved, Path: "data.old", Old: true},
+		{Kind: diff.KindChanged, Path: "data.status", Old: "exists", New: "not_exists"},
+		{Kind: diff.KindRemoved, Path: "data.tags.1", Old: "b"},
+	}, changes)
+}
+
+func TestCompareWithTypeChange(t *testing.T) {
+	changes := diff.Compare(parse(t, `{"data":{"a":1}}`), parse(t, `{"data":[1]}`), nil)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "data", changes[0].Path)
+	assert.Equal(t, `[1]`, diff.Value(changes[0].New))
+}
+
+func TestCompareWithIgnoreRules(t *testing.T) {
+	rules, err := diff.ParseRules([]string{"data.*.createdAt", "meta"})
+	assert.NoError(t, err)
+
+	a := parse(t, `{"data":{"user":{"name":"jane","createdAt":1},"session":{"createdAt":2}},"meta":{"time":1}}`)
+	b := parse(t, `{"data":{"user":{"name":"john","createdAt":3},"session":{}},"meta":{"time":2}}`)
+
+	changes := diff.Compare(a, b, rules)
+	assert.Equal(t, []diff.Change{{Kind: diff.KindChanged, Path: "data.user.name", Old: "jane", New: "john"}}, changes)
+}
+
+func TestParseRulesWithInvalidRule(t *testing.T) {
+	_, err := diff.ParseRules([]string{"body..status"})
+	assert.EqualError(t, err, "invalid ignore rule 'body..status'")
+
+	_, err = diff.ParseRules([]string{""})
+	assert.EqualError(t, err, "ignore rule must not be empty")
+}
+
+func TestExchanges(t *testing.T) {
+	a := &tunnel.Exchange{Response: &tunnel.WebhookResponse{
+		Status:  200,
+		Headers: map[string]string{"Date": "Mon, 02 Jan 2006 15:04:05 GMT", "Content-Type": "application/json"},
+		Body:    `{"data":{"status":"exists"}}`,
+	}}
+	b := &tunnel.Exchange{Response: &tunnel.WebhookResponse{
+		Status:  500,
+		Headers: map[string]string{"Date": "Tue, 03 Jan 2006 15:04:05 GMT", "Content-Type": "text/plain"},
+		Body:    "internal error",
+	}}
+
+	rules, err := diff.ParseRules(diff.DefaultIgnore())
+	assert.NoError(t, err)
+
+	changes := diff.Exchanges(a, b, rules)
+	assert.Len(t, changes, 3)
+	assert.Equal(t, "body", changes[0].Path)
+	assert.Equal(t, "headers.Content-Type", changes[1].Path)
+	assert.Equal(t, "status", changes[2].Path)
+	assert.Equal(t, "200", diff.Value(changes[2].Old))
+
+	failed := &tunnel.Exchange{Error: errors.New("connection refused")}
+	changes = diff.Exchanges(a, failed, rules)
+	assert.Len(t, changes, 4)
+	assert.Equal(t, diff.Change{Kind: diff.KindAdded, Path: "error", New: "connection refused"}, changes[1])
+
+	assert.Empty(t, diff.Exchanges(a, a, rules))
+}
@@ -0,0 +1,160 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+const (
+	FormatHAR    = "har"
+	FormatCurl   = "curl"
+	FormatHTTPie = "httpie"
+)
+
+// ValidateFormat checks if given export format is known
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatHAR, FormatCurl, FormatHTTPie:
+		return nil
+	}
+
+	return errors.Errorf("unknown format '%s' (allowed: har, curl, httpie)", format)
+}
+
+// Extension returns the file extension for given format
+func Extension(format string) string {
+	if format == FormatHAR {
+		return "har"
+	}
+
+	return "sh"
+}
+
+// Write writes given exchanges in given format, requests target given local
+// address (e.g. http://localhost:8000)
+func Write(w io.Writer, format string, exchanges []*tunnel.Exchange, localAddress string) error {
+	localAddress = strings.TrimSuffix(localAddress, "/")
+
+	switch format {
+	case FormatHAR:
+		return writeHAR(w, exchanges, localAddress)
+
+	case FormatCurl:
+		return writeCommands(w, exchanges, localAddress, Curl)
+
+	case FormatHTTPie:
+		return writeCommands(w, exchanges, localAddress, HTTPie)
+	}
+
+	return ValidateFormat(format)
+}
+
+func writeCommands(w io.Writer, exchanges []*tunnel.Exchange, localAddress string, command func(req *tunnel.WebhookRequest, localAddress string) string) error {
+	script := "#!/bin/sh\n"
+	for _, exchange := range exchanges {
+		script += commandLines(exchange, localAddress, command)
+	}
+
+	if _, err := io.WriteString(w, script); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// commandLines returns the command sending given exchange's webhook request
+// with a comment, separated by an empty line from the previous one
+func commandLines(exchange *tunnel.Exchange, localAddress string, command func(req *tunnel.WebhookRequest, localAddress string) string) string {
+	comment := ""
+	for _, name := range sortedNames(exchange.Request.Headers) {
+		if exchange.Request.Headers[name] == redact.Mask {
+			comment += fmt.Sprintf("# %s was redacted when recording, set %s to send it\n", name, Variable(name))
+		}
+	}
+
+	return fmt.Sprintf(
+		"\n# %s %s (%s)\n%s%s\n",
+		exchange.Request.ID,
+		exchange.Request.Path,
+		exchange.Time.Format("2006-01-02 15:04:05"),
+		comment,
+		command(exchange.Request, localAddress),
+	)
+}
+
+// Variable returns the name of the shell variable replacing the redacted
+// value of given header in commands (e.g. CORBADO_WEBHOOK_AUTHORIZATION)
+func Variable(header string) string {
+	name := []byte("CORBADO_WEBHOOK_" + strings.ToUpper(header))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+
+	return string(name)
+}
+
+// header returns given header as quoted shell word, redacted values are
+// replaced by a variable expanded by the shell
+func header(name string, separator string, value string) string {
+	if value == redact.Mask {
+		return quote(name+separator) + `"$` + Variable(name) + `"`
+	}
+
+	return quote(name + separator + value)
+}
+
+// Curl returns a curl command sending given webhook request to given local
+// address, redacted headers are taken from shell variables (see Variable)
+func Curl(req *tunnel.WebhookRequest, localAddress string) string {
+	parts := []string{"curl", "-X", http.MethodPost, quote(localAddress + req.Path)}
+	for _, name := range sortedNames(req.Headers) {
+		parts = append(parts, "-H", header(name, ": ", req.Headers[name]))
+	}
+
+	if req.Body != "" {
+		parts = append(parts, "--data-raw", quote(req.Body))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// HTTPie returns an HTTPie command sending given webhook request to given
+// local address, redacted headers are taken from shell variables (see Variable)
+func HTTPie(req *tunnel.WebhookRequest, localAddress string) string {
+	parts := []string{"http", http.MethodPost, quote(localAddress + req.Path)}
+	for _, name := range sortedNames(req.Headers) {
+		parts = append(parts, header(name, ":", req.Headers[name]))
+	}
+
+	if req.Body != "" {
+		parts = append(parts, "--raw", quote(req.Body))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// quote quotes given value for POSIX shells
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func sortedNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
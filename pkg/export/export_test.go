@@ -0,0 +1,165 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/export"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func exchanges() []*tunnel.Exchange {
+	return []*tunnel.Exchange{
+		{
+			Time:     time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC),
+			Request:  &tunnel.WebhookRequest{ID: "who-1", Path: "/webhook", Headers: map[string]string{"Content-Type": "application/json", "X-Name": "O'Brien"}, Body: `{"username":"o'brien"}`},
+			Response: &tunnel.WebhookResponse{ID: "who-1", Status: http.StatusOK, Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"status":"exists"}`},
+			Latency:  1500 * time.Microsecond,
+		},
+		{
+			Time:     time.Date(2023, 1, 2, 15, 4, 6, 0, time.UTC),
+			Request:  &tunnel.WebhookRequest{ID: "who-2", Path: "/webhook"},
+			Response: &tunnel.WebhookResponse{ID: "who-2", Status: http.StatusServiceUnavailable},
+			Error:    errors.New("local address refused connection"),
+		},
+	}
+}
+
+func TestCurl(t *testing.T) {
+	assert.Equal(
+		t,
+		`curl -X POST 'http://localhost:3000/webhook' -H 'Content-Type: application/json' -H 'X-Name: O'\''Brien' --data-raw '{"username":"o'\''brien"}'`,
+		export.Curl(exchanges()[0].Request, "http://localhost:3000"),
+	)
+}
+
+func TestHTTPie(t *testing.T) {
+	assert.Equal(
+		t,
+		`http POST 'http://localhost:3000/webhook' 'Content-Type:application/json' 'X-Name:O'\''Brien' --raw '{"username":"o'\''brien"}'`,
+		export.HTTPie(exchanges()[0].Request, "http://localhost:3000"),
+	)
+}
+
+func TestWriteCurl(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	assert.NoError(t, export.Write(buffer, export.FormatCurl, exchanges(), "http://localhost:3000/"))
+
+	assert.Equal(t, "#!/bin/sh\n\n"+
+		"# who-1 /webhook (2023-01-02 15:04:05)\n"+
+		export.Curl(exchanges()[0].Request, "http://localhost:3000")+"\n\n"+
+		"# who-2 /webhook (2023-01-02 15:04:06)\n"+
+		"curl -X POST 'http://localhost:3000/webhook'\n", buffer.String())
+}
+
+func TestWriteHAR(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	assert.NoError(t, export.Write(buffer, export.FormatHAR, exchanges(), "http://localhost:3000"))
+
+	var har struct {
+		Log struct {
+			Version string `json:"version"`
+			Entries []struct {
+				StartedDateTime string  `json:"startedDateTime"`
+				Time            float64 `json:"time"`
+				Comment         string  `json:"comment"`
+				Request         struct {
+					Method   string `json:"method"`
+					URL      string `json:"url"`
+					PostData *struct {
+						MimeType string `json:"mimeType"`
+						Text     string `json:"text"`
+					} `json:"postData"`
+				} `json:"request"`
+				Response struct {
+					Status     int    `json:"status"`
+					StatusText string `json:"statusText"`
+					Content    struct {
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"response"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &har))
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Len(t, har.Log.Entries, 2)
+
+	entry := har.Log.Entries[0]
+	assert.Equal(t, "2023-01-02T15:04:05Z", entry.StartedDateTime)
+	assert.Equal(t, 1.5, entry.Time)
+	assert.Equal(t, "POST", entry.Request.Method)
+	assert.Equal(t, "http://localhost:3000/webhook", entry.Request.URL)
+	assert.Equal(t, "application/json", entry.Request.PostData.MimeType)
+	assert.Equal(t, `{"username":"o'brien"}`, entry.Request.PostData.Text)
+	assert.Equal(t, "OK", entry.Response.StatusText)
+	assert.Equal(t, `{"status":"exists"}`, entry.Response.Content.Text)
+
+	assert.Nil(t, har.Log.Entries[1].Request.PostData)
+	assert.Equal(t, "local address refused connection", har.Log.Entries[1].Comment)
+}
+
+func TestFile(t *testing.T) {
+	for _, format := range []string{export.FormatHAR, export.FormatCurl, export.FormatHTTPie} {
+		path := filepath.Join(t.TempDir(), "session."+export.Extension(format))
+
+		file, err := export.Create(path, format, "http://localhost:3000/")
+		assert.NoError(t, err)
+
+		// Complete after every exchange, same content as written at once
+		for i := 0; i <= len(exchanges()); i++ {
+			if i > 0 {
+				assert.NoError(t, file.Add(exchanges()[i-1]))
+			}
+
+			expected := new(bytes.Buffer)
+			assert.NoError(t, export.Write(expected, format, exchanges()[:i], "http://localhost:3000/"))
+
+			content, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, expected.String(), string(content), "%s with %d exchanges", format, i)
+		}
+
+		assert.NoError(t, file.Close())
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	assert.NoError(t, export.ValidateFormat("httpie"))
+	assert.EqualError(t, export.ValidateFormat("postman"), "unknown format 'postman' (allowed: har, curl, httpie)")
+}
+
+func TestWriteCurlWithRedactedHeaders(t *testing.T) {
+	exchange := &tunnel.Exchange{
+		Time:    time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC),
+		Request: &tunnel.WebhookRequest{ID: "who-1", Path: "/webhook", Headers: map[string]string{"Authorization": "[REDACTED]", "X-Api-Key": "[REDACTED]", "X-Name": "jane"}},
+	}
+
+	buffer := new(bytes.Buffer)
+	assert.NoError(t, export.Write(buffer, export.FormatCurl, []*tunnel.Exchange{exchange}, "http://localhost:3000"))
+
+	assert.Equal(t, "#!/bin/sh\n\n"+
+		"# who-1 /webhook (2023-01-02 15:04:05)\n"+
+		"# Authorization was redacted when recording, set CORBADO_WEBHOOK_AUTHORIZATION to send it\n"+
+		"# X-Api-Key was redacted when recording, set CORBADO_WEBHOOK_X_API_KEY to send it\n"+
+		`curl -X POST 'http://localhost:3000/webhook' -H 'Authorization: '"$CORBADO_WEBHOOK_AUTHORIZATION" -H 'X-Api-Key: '"$CORBADO_WEBHOOK_X_API_KEY" -H 'X-Name: jane'`+"\n", buffer.String())
+}
+
+func TestHTTPieWithRedactedHeaders(t *testing.T) {
+	req := &tunnel.WebhookRequest{Path: "/webhook", Headers: map[string]string{"Authorization": "[REDACTED]"}}
+
+	assert.Equal(
+		t,
+		`http POST 'http://localhost:3000/webhook' 'Authorization:'"$CORBADO_WEBHOOK_AUTHORIZATION"`,
+		export.HTTPie(req, "http://localhost:3000"),
+	)
+}
@@ -0,0 +1,136 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// File writes exchanges one after another to a file (e.g. during subscribe),
+// earlier exchanges are neither kept in memory nor written again. The file is
+// complete after every exchange, HAR files get their closing part rewritten.
+type File struct {
+	file         *os.File
+	format       string
+	localAddress string
+	entries      int
+	tail         []byte
+}
+
+// Create creates (or truncates) given file for exchanges in given format,
+// requests target given local address (e.g. http://localhost:8000)
+func Create(path string, format string, localAddress string) (*File, error) {
+	if err := ValidateFormat(format); err != nil {
+		return nil, err
+	}
+
+	mode := os.FileMode(0o600)
+	if format != FormatHAR {
+		// Shell scripts with curl or HTTPie commands are ready to run
+		mode = 0o700
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	f := &File{
+		file:         file,
+		format:       format,
+		localAddress: strings.TrimSuffix(localAddress, "/"),
+	}
+
+	if err := f.writeHeader(); err != nil {
+		_ = file.Close()
+
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Add appends given exchange
+func (f *File) Add(exchange *tunnel.Exchange) error {
+	switch f.format {
+	case FormatHAR:
+		return f.addHAREntry(exchange)
+
+	case FormatCurl:
+		return f.addCommand(exchange, Curl)
+
+	default:
+		return f.addCommand(exchange, HTTPie)
+	}
+}
+
+// Close closes the file
+func (f *File) Close() error {
+	return errors.WithStack(f.file.Close())
+}
+
+func (f *File) writeHeader() error {
+	if f.format != FormatHAR {
+		return f.write([]byte("#!/bin/sh\n"))
+	}
+
+	// Same layout as writeHAR, the empty entries are replaced by every entry
+	encoded, err := json.MarshalIndent(newHARFile(0), "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	i := bytes.Index(encoded, []byte("[]"))
+	f.tail = append(encoded[i+1:], '\n')
+
+	return f.write(encoded[:i+1], f.tail)
+}
+
+func (f *File) addCommand(exchange *tunnel.Exchange, command func(req *tunnel.WebhookRequest, localAddress string) string) error {
+	return f.write([]byte(commandLines(exchange, f.localAddress, command)))
+}
+
+func (f *File) addHAREntry(exchange *tunnel.Exchange) error {
+	// Entries are indented two more levels than the log
+	const indent = "      "
+
+	encoded, err := json.MarshalIndent(newHAREntry(exchange, f.localAddress), indent, "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// Overwrite the closing part of the previous write
+	if _, err := f.file.Seek(-int64(len(f.tail)), io.SeekEnd); err != nil {
+		return errors.WithStack(err)
+	}
+
+	separator := ",\n" + indent
+	if f.entries == 0 {
+		separator = "\n" + indent
+		f.tail = append([]byte("\n    "), f.tail...)
+	}
+
+	if err := f.write([]byte(separator), encoded, f.tail); err != nil {
+		return err
+	}
+
+	f.entries++
+
+	return nil
+}
+
+func (f *File) write(parts ...[]byte) error {
+	for _, part := range parts {
+		if _, err := f.file.Write(part); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
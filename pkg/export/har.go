@@ -0,0 +1,174 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// HAR 1.2 (http://www.softwareishard.com/blog/har-12-spec/), only the parts
+// needed for webhook requests
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []struct{}     `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []struct{}     `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func writeHAR(w io.Writer, exchanges []*tunnel.Exchange, localAddress string) error {
+	file := newHARFile(len(exchanges))
+	for _, exchange := range exchanges {
+		file.Log.Entries = append(file.Log.Entries, newHAREntry(exchange, localAddress))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(file); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func newHARFile(entries int) *harFile {
+	return &harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "corbado", Version: "1"},
+		Entries: make([]harEntry, 0, entries),
+	}}
+}
+
+func newHAREntry(exchange *tunnel.Exchange, localAddress string) harEntry {
+	req := exchange.Request
+	latency := float64(exchange.Latency) / float64(time.Millisecond)
+
+	entry := harEntry{
+		StartedDateTime: exchange.Time.Format(time.RFC3339Nano),
+		Time:            latency,
+		Request: harRequest{
+			Method:      http.MethodPost,
+			URL:         localAddress + req.Path,
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []struct{}{},
+			Headers:     harHeaders(req.Headers),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(req.Body),
+		},
+		Response: harResponse{
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []struct{}{},
+			Headers:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: harTimings{Wait: latency},
+	}
+
+	if req.Body != "" {
+		entry.Request.PostData = &harPostData{MimeType: mimeType(req.Headers), Text: req.Body}
+	}
+
+	if resp := exchange.Response; resp != nil {
+		entry.Response.Status = resp.Status
+		entry.Response.StatusText = http.StatusText(resp.Status)
+		entry.Response.Headers = harHeaders(resp.Headers)
+		entry.Response.Content = harContent{Size: len(resp.Body), MimeType: mimeType(resp.Headers), Text: resp.Body}
+		entry.Response.BodySize = len(resp.Body)
+	}
+
+	if exchange.Error != nil {
+		entry.Comment = exchange.Error.Error()
+	}
+
+	return entry
+}
+
+func harHeaders(headers map[string]string) []harNameValue {
+	result := make([]harNameValue, 0, len(headers))
+	for _, name := range sortedNames(headers) {
+		result = append(result, harNameValue{Name: name, Value: headers[name]})
+	}
+
+	return result
+}
+
+func mimeType(headers map[string]string) string {
+	for name, value := range headers {
+		if http.CanonicalHeaderKey(name) == "Content-Type" {
+			return value
+		}
+	}
+
+	return ""
+}
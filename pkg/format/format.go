@@ -0,0 +1,48 @@
+package format
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	kibibyte = 1024
+	mebibyte = 1024 * kibibyte
+	gibibyte = 1024 * mebibyte
+)
+
+// Bytes formats given number of bytes with a binary unit (B, KiB, MiB or GiB)
+func Bytes(bytes int) string {
+	switch {
+	case bytes >= gibibyte:
+		return fmt.Sprintf("%.2f GiB", float64(bytes)/gibibyte)
+
+	case bytes >= mebibyte:
+		return fmt.Sprintf("%.2f MiB", float64(bytes)/mebibyte)
+
+	case bytes >= kibibyte:
+		return fmt.Sprintf("%.2f KiB", float64(bytes)/kibibyte)
+
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}
+
+// Duration formats given duration with a unit fitting its magnitude (µs, ms or s)
+func Duration(duration time.Duration) string {
+	switch {
+	case duration >= time.Second:
+		return fmt.Sprintf("%.2f s", duration.Seconds())
+
+	case duration >= time.Millisecond:
+		return fmt.Sprintf("%.2f ms", float64(duration)/float64(time.Millisecond))
+
+	default:
+		return fmt.Sprintf("%d µs", duration.Microseconds())
+	}
+}
+
+// Latency formats given duration as fixed width column so that console lines stay aligned
+func Latency(duration time.Duration) string {
+	return fmt.Sprintf("%10s", Duration(duration))
+}
@@ -0,0 +1,30 @@
+package format_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/format"
+)
+
+func TestBytes(t *testing.T) {
+	assert.Equal(t, "0 B", format.Bytes(0))
+	assert.Equal(t, "1023 B", format.Bytes(1023))
+	assert.Equal(t, "1.00 KiB", format.Bytes(1024))
+	assert.Equal(t, "1.50 KiB", format.Bytes(1536))
+	assert.Equal(t, "3.00 MiB", format.Bytes(3*1024*1024))
+	assert.Equal(t, "2.00 GiB", format.Bytes(2*1024*1024*1024))
+}
+
+func TestDuration(t *testing.T) {
+	assert.Equal(t, "0 µs", format.Duration(0))
+	assert.Equal(t, "250 µs", format.Duration(250*time.Microsecond))
+	assert.Equal(t, "12.50 ms", format.Duration(12500*time.Microsecond))
+	assert.Equal(t, "10.00 s", format.Duration(10*time.Second))
+}
+
+func TestLatency(t *testing.T) {
+	assert.Equal(t, "  12.50 ms", format.Latency(12500*time.Microsecond))
+}
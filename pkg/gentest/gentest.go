@@ -0,0 +1,162 @@
+package gentest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/history"
+	"github.com/corbado/cli/pkg/redact"
+)
+
+const LangGo = "go"
+
+// Options defines how the test is generated
+type Options struct {
+	// Package is the package clause of the test file
+	Package string
+
+	// Handler is a Go expression of type http.Handler the test sends the
+	// webhook request to (e.g. NewRouter())
+	Handler string
+
+	// Name is the name of the test function (generated from the record if empty)
+	Name string
+}
+
+type header struct {
+	Name     string
+	Value    string
+	Redacted bool
+}
+
+type testData struct {
+	Record           *history.Record
+	Options          *Options
+	Time             string
+	Path             string
+	Headers          []header
+	Body             string
+	BodyRedacted     bool
+	Status           int
+	ContentType      string
+	ResponseBody     string
+	ResponseIsJSON   bool
+	ResponseRedacted bool
+	CheckResponse    bool
+}
+
+var nameCleaner = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// ValidateLang checks if tests can be generated for given language
+func ValidateLang(lang string) error {
+	if lang != LangGo {
+		return errors.Errorf("unsupported language '%s' (allowed: go)", lang)
+	}
+
+	return nil
+}
+
+// Go returns a gofmt-ed Go test file which sends the recorded webhook request
+// to a handler using net/http/httptest and asserts the recorded response
+func Go(record *history.Record, options *Options) ([]byte, error) {
+	if options.Name == "" {
+		options.Name = fmt.Sprintf("TestWebhook%s%d", cleanName(record.Request.Path), record.ID)
+	}
+
+	data := &testData{
+		Record:  record,
+		Options: options,
+		Time:    record.Time.Format("2006-01-02 15:04:05"),
+		Path:    strconv.Quote(record.Request.Path),
+		Body:    quote(record.Request.Body),
+	}
+
+	for _, name := range sortedNames(record.Request.Headers) {
+		value := record.Request.Headers[name]
+		data.Headers = append(data.Headers, header{Name: strconv.Quote(name), Value: strconv.Quote(value), Redacted: value == redact.Mask})
+	}
+
+	data.BodyRedacted = strings.Contains(record.Request.Body, redact.Mask)
+
+	// Failed deliveries (timeouts, local errors) have no response worth pinning
+	if resp := record.Response; resp != nil && record.Error == "" && !record.TimedOut {
+		data.CheckResponse = true
+		data.Status = resp.Status
+		data.ContentType = strconv.Quote(contentType(resp.Headers))
+		data.ResponseBody = quote(resp.Body)
+		data.ResponseIsJSON = json.Valid([]byte(resp.Body))
+		data.ResponseRedacted = strings.Contains(resp.Body, redact.Mask)
+	}
+
+	tmpl, err := template.New("test").Parse(goTemplate)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := tmpl.Execute(buffer, data); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "formatting generated test (check --handler)")
+	}
+
+	return source, nil
+}
+
+// quote returns given string as Go string literal, as raw string if possible
+func quote(s string) string {
+	if !strings.ContainsAny(s, "`\r") && strings.ToValidUTF8(s, "") == s {
+		return "`" + s + "`"
+	}
+
+	return strconv.Quote(s)
+}
+
+func cleanName(path string) string {
+	parts := nameCleaner.Split(path, -1)
+
+	var name strings.Builder
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		name.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return name.String()
+}
+
+func contentType(headers map[string]string) string {
+	for name, value := range headers {
+		if http.CanonicalHeaderKey(name) == "Content-Type" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func sortedNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
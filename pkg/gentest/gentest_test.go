@@ -0,0 +1,135 @@
+package gentest_test
+
+import (
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/gentest"
+	"github.com/corbado/cli/pkg/history"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func record() *history.Record {
+	return &history.Record{
+		ID:   42,
+		Time: time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC),
+		Request: &tunnel.WebhookRequest{
+			ID:      "who-1",
+			Path:    "/webhooks/corbado",
+			Headers: map[string]string{"Authorization": "[REDACTED]", "Content-Type": "application/json"},
+			Body:    `{"action":"authMethods","data":{"username":"jane"}}`,
+		},
+		Response: &tunnel.WebhookResponse{
+			Status:  http.StatusOK,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    `{"data":{"status":"exists"}}`,
+		},
+	}
+}
+
+func TestGo(t *testing.T) {
+	source, err := gentest.Go(record(), &gentest.Options{Package: "webhooks", Handler: "NewRouter()"})
+	assert.NoError(t, err)
+
+	code := string(source)
+	assert.Contains(t, code, "package webhooks\n")
+	assert.Contains(t, code, "func TestWebhookWebhooksCorbado42(t *testing.T) {")
+	assert.Contains(t, code, "var handler http.Handler = NewRouter()")
+	assert.Contains(t, code, "body := `{\"action\":\"authMethods\",\"data\":{\"username\":\"jane\"}}`")
+	assert.Contains(t, code, `httptest.NewRequest(http.MethodPost, "/webhooks/corbado", strings.NewReader(body))`)
+	assert.Contains(t, code, `// req.Header.Set("Authorization", "")`)
+	assert.Contains(t, code, `req.Header.Set("Content-Type", "application/json")`)
+	assert.Contains(t, code, "if rec.Code != 200 {")
+	assert.Contains(t, code, "reflect.DeepEqual(expected, actual)")
+}
+
+func TestGoWithoutResponse(t *testing.T) {
+	r := record()
+	r.Error = "local address refused connection"
+	r.Request.Body = "line 1\r\nline `2`"
+
+	source, err := gentest.Go(r, &gentest.Options{Package: "main", Handler: "http.DefaultServeMux", Name: "TestRefused"})
+	assert.NoError(t, err)
+
+	code := string(source)
+	assert.Contains(t, code, "func TestRefused(t *testing.T) {")
+	assert.Contains(t, code, "body := \"line 1\\r\\nline `2`\"")
+	assert.NotContains(t, code, "rec.Code")
+	assert.NotContains(t, code, "encoding/json")
+}
+
+func TestGoWithRedactedResponse(t *testing.T) {
+	r := record()
+	r.Response.Body = `{"data":{"token":"[REDACTED]"}}`
+
+	source, err := gentest.Go(r, &gentest.Options{Package: "webhooks", Handler: "NewRouter()"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(source), "actual = skipRedacted(expected, actual)")
+
+	r.Response.Headers = nil
+	r.Response.Body = "token [REDACTED]"
+
+	source, err = gentest.Go(r, &gentest.Options{Package: "webhooks", Handler: "NewRouter()"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(source), "// The body was redacted when recording, so it is not compared")
+	assert.NotContains(t, string(source), "rec.Body.String() != expectedBody")
+}
+
+func TestGoWithInvalidHandler(t *testing.T) {
+	_, err := gentest.Go(record(), &gentest.Options{Package: "main", Handler: "NewRouter("})
+	assert.Error(t, err)
+}
+
+// TestGoRuns compiles and runs a generated test against a handler answering
+// like the recorded response
+func TestGoRuns(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test")
+	}
+
+	goBinary, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found")
+	}
+
+	// Redacted response fields are not compared
+	r := record()
+	r.Response.Body = `{"data":{"status":"exists","token":"[REDACTED]"}}`
+
+	source, err := gentest.Go(r, &gentest.Options{Package: "webhooks", Handler: "handler()"})
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/webhooks\n\ngo 1.19\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "webhook_test.go"), source, 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "handler.go"), []byte(`package webhooks
+
+import "net/http"
+
+func handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/webhooks/corbado" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`+"`"+`{ "data": { "status": "exists", "token": "abc" } }`+"`"+`))
+	})
+}
+`), 0o600))
+
+	cmd := exec.Command(goBinary, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(output))
+}
@@ -0,0 +1,114 @@
+package gentest
+
+const goTemplate = `package {{.Options.Package}}
+
+import (
+{{- if .ResponseIsJSON}}
+	"encoding/json"
+{{- end}}
+	"net/http"
+	"net/http/httptest"
+{{- if .ResponseIsJSON}}
+	"reflect"
+{{- end}}
+	"strings"
+	"testing"
+)
+
+// {{.Options.Name}} replays webhook request {{.Record.Request.ID}} recorded at {{.Time}}
+// (generated by corbado history gen-test {{.Record.ID}})
+func {{.Options.Name}}(t *testing.T) {
+	var handler http.Handler = {{.Options.Handler}}
+
+	body := {{.Body}}
+{{- if .BodyRedacted}}
+	// Some body fields were redacted when recording, replace them if your handler needs them
+{{- end}}
+
+	req := httptest.NewRequest(http.MethodPost, {{.Path}}, strings.NewReader(body))
+{{- range .Headers}}
+{{- if .Redacted}}
+	// {{.Name}} was redacted when recording, set it if your handler checks it
+	// req.Header.Set({{.Name}}, "")
+{{- else}}
+	req.Header.Set({{.Name}}, {{.Value}})
+{{- end}}
+{{- end}}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+{{- if .CheckResponse}}
+
+	if rec.Code != {{.Status}} {
+		t.Fatalf("expected status %d, got %d (body: %s)", {{.Status}}, rec.Code, rec.Body.String())
+	}
+{{- if ne .ContentType "\"\""}}
+
+	if contentType := rec.Header().Get("Content-Type"); contentType != {{.ContentType}} {
+		t.Errorf("expected Content-Type %q, got %q", {{.ContentType}}, contentType)
+	}
+{{- end}}
+
+{{- if and .ResponseRedacted (not .ResponseIsJSON)}}
+
+	// The body was redacted when recording, so it is not compared
+{{- else}}
+
+	expectedBody := {{.ResponseBody}}
+{{- if .ResponseIsJSON}}
+
+	var expected, actual any
+	if err := json.Unmarshal([]byte(expectedBody), &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &actual); err != nil {
+		t.Fatalf("expected JSON body, got %s", rec.Body.String())
+	}
+{{- if .ResponseRedacted}}
+
+	// Some body fields were redacted when recording, they are not compared
+	var skipRedacted func(expected any, actual any) any
+	skipRedacted = func(expected any, actual any) any {
+		switch e := expected.(type) {
+		case string:
+			if e == "[REDACTED]" {
+				return e
+			}
+
+		case map[string]any:
+			if a, ok := actual.(map[string]any); ok {
+				for key, value := range e {
+					if actualValue, ok := a[key]; ok {
+						a[key] = skipRedacted(value, actualValue)
+					}
+				}
+			}
+
+		case []any:
+			if a, ok := actual.([]any); ok {
+				for i := 0; i < len(e) && i < len(a); i++ {
+					a[i] = skipRedacted(e[i], a[i])
+				}
+			}
+		}
+
+		return actual
+	}
+
+	actual = skipRedacted(expected, actual)
+{{- end}}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected body %s, got %s", expectedBody, rec.Body.String())
+	}
+{{- else}}
+
+	if rec.Body.String() != expectedBody {
+		t.Errorf("expected body %q, got %q", expectedBody, rec.Body.String())
+	}
+{{- end}}
+{{- end}}
+{{- end}}
+}
+`
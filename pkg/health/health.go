@@ -0,0 +1,107 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// LocalCheck checks if the local address is reachable
+type LocalCheck func() error
+
+type Health struct {
+	localAddress string
+	status       tunnel.StatusSource
+	localCheck   LocalCheck
+}
+
+type Response struct {
+	Status string         `json:"status"`
+	Tunnel TunnelResponse `json:"tunnel"`
+	Local  *LocalResponse `json:"local,omitempty"`
+}
+
+type TunnelResponse struct {
+	Connected       bool       `json:"connected"`
+	ConnectedSince  *time.Time `json:"connectedSince,omitempty"`
+	LastMessage     *time.Time `json:"lastMessage,omitempty"`
+	Reconnects      int        `json:"reconnects"`
+	WebsocketErrors int        `json:"websocketErrors"`
+}
+
+type LocalResponse struct {
+	Address   string `json:"address"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+}
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// New returns new health instance for given local address, the local check is
+// optional (e.g. there is no local address in echo mode)
+func New(localAddress string, status tunnel.StatusSource, localCheck LocalCheck) *Health {
+	return &Health{
+		localAddress: localAddress,
+		status:       status,
+		localCheck:   localCheck,
+	}
+}
+
+// Check returns the current health, the status is down if the tunnel is not connected
+func (h *Health) Check() *Response {
+	status := h.status()
+
+	response := &Response{
+		Status: StatusUp,
+		Tunnel: TunnelResponse{
+			Connected:       status.Connected,
+			ConnectedSince:  optionalTime(status.ConnectedSince),
+			LastMessage:     optionalTime(status.LastMessage),
+			Reconnects:      status.Reconnects,
+			WebsocketErrors: status.WebsocketErrors,
+		},
+	}
+
+	if !status.Connected {
+		response.Status = StatusDown
+	}
+
+	if h.localCheck != nil {
+		response.Local = &LocalResponse{
+			Address:   h.localAddress,
+			Reachable: true,
+		}
+
+		if err := h.localCheck(); err != nil {
+			response.Local.Reachable = false
+			response.Local.Error = err.Error()
+		}
+	}
+
+	return response
+}
+
+// ServeHTTP writes the current health as JSON, with status 503 if the tunnel is down
+func (h *Health) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	response := h.Check()
+
+	w.Header().Set("Content-Type", "application/json")
+	if response.Status == StatusDown {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
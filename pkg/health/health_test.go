@@ -0,0 +1,68 @@
+package health_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/health"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func serve(t *testing.T, h *health.Health) (int, *health.Response) {
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	response := &health.Response{}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), response))
+
+	return recorder.Code, response
+}
+
+func TestHealthUp(t *testing.T) {
+	lastMessage := time.Now()
+	h := health.New("http://localhost:8000", func() tunnel.Status {
+		return tunnel.Status{Connected: true, ConnectedSince: lastMessage, LastMessage: lastMessage, Reconnects: 1}
+	}, func() error {
+		return nil
+	})
+
+	code, response := serve(t, h)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusUp, response.Status)
+	assert.True(t, response.Tunnel.Connected)
+	assert.Equal(t, 1, response.Tunnel.Reconnects)
+	assert.NotNil(t, response.Tunnel.LastMessage)
+	assert.True(t, response.Local.Reachable)
+	assert.Equal(t, "http://localhost:8000", response.Local.Address)
+}
+
+func TestHealthDown(t *testing.T) {
+	h := health.New("http://localhost:8000", func() tunnel.Status {
+		return tunnel.Status{}
+	}, func() error {
+		return errors.New("localhost:8000 not reachable")
+	})
+
+	code, response := serve(t, h)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusDown, response.Status)
+	assert.Nil(t, response.Tunnel.LastMessage)
+	assert.False(t, response.Local.Reachable)
+	assert.Equal(t, "localhost:8000 not reachable", response.Local.Error)
+}
+
+func TestHealthWithoutLocalCheck(t *testing.T) {
+	h := health.New("", func() tunnel.Status {
+		return tunnel.Status{Connected: true}
+	}, nil)
+
+	code, response := serve(t, h)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, response.Local)
+}
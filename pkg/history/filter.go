@@ -0,0 +1,91 @@
+package history
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/match"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// Filter selects records, all given criteria must match
+type Filter struct {
+	Since time.Time
+	Until time.Time
+
+	// Path must be contained in the path of the webhook request
+	Path string
+
+	// Status lists conditions of which one must match (see tunnel.MatchCondition)
+	Status []string
+
+	// Matchers check the webhook request (e.g. body.data.username=jane)
+	Matchers []*match.Matcher
+
+	// Limit is the maximum number of records returned (0 means unlimited)
+	Limit int
+}
+
+// Match returns true if given record matches the filter
+func (f *Filter) Match(record *Record) bool {
+	if !f.Since.IsZero() && record.Time.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && record.Time.After(f.Until) {
+		return false
+	}
+
+	if f.Path != "" && !strings.Contains(record.Request.Path, f.Path) {
+		return false
+	}
+
+	if len(f.Status) > 0 {
+		exchange := record.Exchange()
+
+		matched := false
+		for _, condition := range f.Status {
+			if tunnel.MatchCondition(condition, exchange) {
+				matched = true
+
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return match.MatchAll(f.Matchers, record.Request)
+}
+
+// ParseTime parses given time relative to now: a duration (e.g. 24h means 24
+// hours ago), a date (2006-01-02), a local date and time (2006-01-02 15:04) or
+// RFC 3339
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	if strings.HasSuffix(value, "d") {
+		if n, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, errors.Errorf("invalid time '%s' (allowed: duration like 24h or 7d, 2006-01-02, 2006-01-02 15:04 or RFC 3339)", value)
+}
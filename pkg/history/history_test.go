@@ -0,0 +1,153 @@
+package history_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/history"
+	"github.com/corbado/cli/pkg/match"
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func exchange(at time.Time, path string, status int, body string) *tunnel.Exchange {
+	return &tunnel.Exchange{
+		Time:     at,
+		Request:  &tunnel.WebhookRequest{ID: "who-1", Path: path, Headers: map[string]string{"Authorization": "Basic secret"}, Body: body},
+		Response: &tunnel.WebhookResponse{ID: "who-1", Status: status},
+		Attempts: 1,
+	}
+}
+
+func TestStoreAddGetList(t *testing.T) {
+	store := history.New(filepath.Join(t.TempDir(), "history.db"), history.Retention{})
+
+	records, err := store.List(&history.Filter{})
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	_, err = store.Get(1)
+	assert.ErrorIs(t, err, history.ErrNotFound)
+
+	now := time.Now()
+	redactor := redact.New(nil, []string{"data.password"})
+
+	_, err = store.Add(exchange(now.Add(-26*time.Hour), "/users", http.StatusOK, `{"data":{"username":"jane","password":"secret"}}`), redactor)
+	assert.NoError(t, err)
+
+	_, err = store.Add(exchange(now.Add(-25*time.Hour), "/users", http.StatusInternalServerError, `{"data":{"username":"john"}}`), redactor)
+	assert.NoError(t, err)
+
+	failed := exchange(now, "/sessions", http.StatusServiceUnavailable, "")
+	failed.Error = &tunnel.LocalError{Kind: tunnel.LocalErrorRefused, Message: "refused"}
+	record, err := store.Add(failed, redactor)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), record.ID)
+
+	record, err = store.Get(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "[REDACTED]", record.Request.Headers["Authorization"])
+	assert.Equal(t, `{"data":{"password":"[REDACTED]","username":"jane"}}`, record.Request.Body)
+
+	records, err = store.List(&history.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 2, 1}, ids(records))
+
+	// Refused connection was answered with 503 as well
+	records, err = store.List(&history.Filter{Status: []string{"5xx"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 2}, ids(records))
+
+	records, err = store.List(&history.Filter{Status: []string{"500"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2}, ids(records))
+
+	records, err = store.List(&history.Filter{Status: []string{"refused"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3}, ids(records))
+
+	records, err = store.List(&history.Filter{Path: "/user", Until: now.Add(-time.Hour), Since: now.Add(-48 * time.Hour), Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{2}, ids(records))
+
+	matchers, err := match.ParseAll([]string{"body.data.username=jane"})
+	assert.NoError(t, err)
+
+	records, err = store.List(&history.Filter{Matchers: matchers})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1}, ids(records))
+}
+
+func TestStoreRetention(t *testing.T) {
+	store := history.New(filepath.Join(t.TempDir(), "history.db"), history.Retention{MaxAge: 24 * time.Hour, MaxRecords: 2})
+	redactor := redact.New(nil, nil)
+	now := time.Now()
+
+	for _, at := range []time.Time{now.Add(-48 * time.Hour), now.Add(-time.Minute), now.Add(-time.Second), now} {
+		_, err := store.Add(exchange(at, "/webhook", http.StatusOK, ""), redactor)
+		assert.NoError(t, err)
+	}
+
+	records, err := store.List(&history.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{4, 3}, ids(records))
+}
+
+func TestStoreAddAll(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history.db")
+	redactor := redact.New(nil, nil)
+	now := time.Now()
+
+	store := history.New(file, history.Retention{})
+	for _, at := range []time.Time{now.Add(-48 * time.Hour), now.Add(-time.Minute)} {
+		_, err := store.Add(exchange(at, "/webhook", http.StatusOK, ""), redactor)
+		assert.NoError(t, err)
+	}
+
+	// Retention is applied on the first write of a session
+	store = history.New(file, history.Retention{MaxAge: 24 * time.Hour})
+
+	records, err := store.AddAll([]*tunnel.Exchange{exchange(now, "/webhook", http.StatusOK, ""), exchange(now, "/other", http.StatusOK, "")}, redactor)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3, 4}, ids(records))
+
+	// The file is not locked in between, so it can be read meanwhile
+	records, err = history.New(file, history.Retention{}).List(&history.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{4, 3, 2}, ids(records))
+
+	_, err = store.AddAll([]*tunnel.Exchange{exchange(now, "/webhook", http.StatusOK, "")}, redactor)
+	assert.NoError(t, err)
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2023, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	for value, expected := range map[string]time.Time{
+		"24h":                  time.Date(2023, 5, 9, 12, 0, 0, 0, time.UTC),
+		"7d":                   time.Date(2023, 5, 3, 12, 0, 0, 0, time.UTC),
+		"2023-05-09":           time.Date(2023, 5, 9, 0, 0, 0, 0, time.UTC),
+		"2023-05-09 15:30":     time.Date(2023, 5, 9, 15, 30, 0, 0, time.UTC),
+		"2023-05-09T15:30:00Z": time.Date(2023, 5, 9, 15, 30, 0, 0, time.UTC),
+	} {
+		actual, err := history.ParseTime(value, now)
+		assert.NoError(t, err, value)
+		assert.True(t, expected.Equal(actual), value)
+	}
+
+	_, err := history.ParseTime("yesterday", now)
+	assert.Error(t, err)
+}
+
+func ids(records []*history.Record) []uint64 {
+	result := make([]uint64, 0, len(records))
+	for _, record := range records {
+		result = append(result, record.ID)
+	}
+
+	return result
+}
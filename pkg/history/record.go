@@ -0,0 +1,72 @@
+package history
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// Record is a stored exchange
+type Record struct {
+	ID        uint64                  `json:"id"`
+	Time      time.Time               `json:"time"`
+	Request   *tunnel.WebhookRequest  `json:"request"`
+	Response  *tunnel.WebhookResponse `json:"response,omitempty"`
+	Latency   time.Duration           `json:"latency"`
+	Attempts  int                     `json:"attempts"`
+	TimedOut  bool                    `json:"timedOut"`
+	Error     string                  `json:"error,omitempty"`
+	ErrorKind string                  `json:"errorKind,omitempty"`
+}
+
+// newRecord returns a record of given exchange, request and response are
+// stored the way given redactor masks them
+func newRecord(exchange *tunnel.Exchange, redactor *redact.Redactor) *Record {
+	record := &Record{
+		Time:     exchange.Time,
+		Request:  exchange.Request.Redacted(redactor),
+		Latency:  exchange.Latency,
+		Attempts: exchange.Attempts,
+		TimedOut: exchange.TimedOut,
+	}
+
+	if exchange.Response != nil {
+		record.Response = exchange.Response.Redacted(redactor)
+	}
+
+	if exchange.Error != nil {
+		record.Error = exchange.Error.Error()
+
+		var localErr *tunnel.LocalError
+		if errors.As(exchange.Error, &localErr) {
+			record.ErrorKind = localErr.Kind
+		}
+	}
+
+	return record
+}
+
+// Exchange returns the record as exchange again (e.g. for tunnel.MatchCondition)
+func (r *Record) Exchange() *tunnel.Exchange {
+	exchange := &tunnel.Exchange{
+		Time:     r.Time,
+		Request:  r.Request,
+		Response: r.Response,
+		Latency:  r.Latency,
+		Attempts: r.Attempts,
+		TimedOut: r.TimedOut,
+	}
+
+	switch {
+	case r.ErrorKind != "":
+		exchange.Error = &tunnel.LocalError{Kind: r.ErrorKind, Message: r.Error}
+
+	case r.Error != "":
+		exchange.Error = errors.New(r.Error)
+	}
+
+	return exchange
+}
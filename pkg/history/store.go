@@ -0,0 +1,278 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+const (
+	bucketName  = "exchanges"
+	lockTimeout = 5 * time.Second
+
+	// retentionInterval is the number of records added by AddAll before the
+	// retention limits are applied again
+	retentionInterval = 100
+)
+
+// ErrNotFound is returned if a record does not exist
+var ErrNotFound = errors.New("Webhook request not found in history")
+
+// Retention limits how many records are kept, zero values mean unlimited
+type Retention struct {
+	MaxAge     time.Duration
+	MaxRecords int
+}
+
+// Store persists exchanges in an embedded database file. The file is only
+// opened (and locked) for the duration of each operation, so that history
+// commands can read it while a subscribe session writes to it.
+type Store struct {
+	path      string
+	retention Retention
+	added     int
+}
+
+// New returns new store using given database file (created on first write)
+func New(path string, retention Retention) *Store {
+	return &Store{
+		path:      path,
+		retention: retention,
+	}
+}
+
+// Add stores given exchange (masked by given redactor) and applies the
+// retention limits, it returns the stored record
+func (s *Store) Add(exchange *tunnel.Exchange, redactor *redact.Redactor) (*Record, error) {
+	records, err := s.add([]*tunnel.Exchange{exchange}, redactor, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return records[0], nil
+}
+
+// AddAll stores given exchanges (masked by given redactor) in a single write,
+// the retention limits are applied on the first call and then only every 100
+// records (e.g. for a subscribe session storing every webhook request)
+func (s *Store) AddAll(exchanges []*tunnel.Exchange, redactor *redact.Redactor) ([]*Record, error) {
+	retention := s.added == 0 || s.added/retentionInterval != (s.added+len(exchanges))/retentionInterval
+
+	return s.add(exchanges, redactor, retention)
+}
+
+func (s *Store) add(exchanges []*tunnel.Exchange, redactor *redact.Redactor, retention bool) ([]*Record, error) {
+	records := make([]*Record, 0, len(exchanges))
+
+	err := s.update(func(bucket *bolt.Bucket) error {
+		for _, exchange := range exchanges {
+			record := newRecord(exchange, redactor)
+
+			id, err := bucket.NextSequence()
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			record.ID = id
+
+			value, err := json.Marshal(record)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			if err := bucket.Put(key(id), value); err != nil {
+				return errors.WithStack(err)
+			}
+
+			records = append(records, record)
+		}
+
+		if !retention {
+			return nil
+		}
+
+		return s.applyRetention(bucket)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.added += len(exchanges)
+
+	return records, nil
+}
+
+// Get returns the record with given ID
+func (s *Store) Get(id uint64) (*Record, error) {
+	var record *Record
+
+	err := s.view(func(bucket *bolt.Bucket) error {
+		value := bucket.Get(key(id))
+		if value == nil {
+			return nil
+		}
+
+		var err error
+		record, err = decode(value)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if record == nil {
+		return nil, ErrNotFound
+	}
+
+	return record, nil
+}
+
+// List returns all records matching given filter, newest first
+func (s *Store) List(filter *Filter) ([]*Record, error) {
+	var records []*Record
+
+	err := s.view(func(bucket *bolt.Bucket) error {
+		cursor := bucket.Cursor()
+		for k, value := cursor.Last(); k != nil; k, value = cursor.Prev() {
+			record, err := decode(value)
+			if err != nil {
+				return err
+			}
+
+			if !filter.Match(record) {
+				continue
+			}
+
+			records = append(records, record)
+			if filter.Limit > 0 && len(records) >= filter.Limit {
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// applyRetention deletes the oldest records exceeding the limits
+func (s *Store) applyRetention(bucket *bolt.Bucket) error {
+	// Stats are not up to date within a write transaction, so count the keys
+	count := 0
+	cursor := bucket.Cursor()
+	for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+		count++
+	}
+
+	cutoff := time.Time{}
+	if s.retention.MaxAge > 0 {
+		cutoff = time.Now().Add(-s.retention.MaxAge)
+	}
+
+	// Deleting while iterating skips keys, so collect them first
+	var expired [][]byte
+
+	for k, value := cursor.First(); k != nil; k, value = cursor.Next() {
+		tooMany := s.retention.MaxRecords > 0 && count-len(expired) > s.retention.MaxRecords
+		if !tooMany {
+			record, err := decode(value)
+			if err != nil {
+				return err
+			}
+
+			if cutoff.IsZero() || !record.Time.Before(cutoff) {
+				break
+			}
+		}
+
+		expired = append(expired, append([]byte(nil), k...))
+	}
+
+	for _, k := range expired {
+		if err := bucket.Delete(k); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) update(fn func(bucket *bolt.Bucket) error) error {
+	db, err := openDB(s.path, false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(bucketName))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		return fn(bucket)
+	})
+}
+
+// view runs given function read-only, it is not called at all if nothing
+// was stored yet
+func (s *Store) view(fn func(bucket *bolt.Bucket) error) error {
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	db, err := openDB(s.path, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return nil
+		}
+
+		return fn(bucket)
+	})
+}
+
+func openDB(path string, readOnly bool) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: lockTimeout, ReadOnly: readOnly})
+	if err != nil {
+		if errors.Is(err, bolt.ErrTimeout) {
+			return nil, errors.Errorf("History %s is in use by another process", path)
+		}
+
+		return nil, errors.Wrapf(err, "opening history %s", path)
+	}
+
+	return db, nil
+}
+
+func key(id uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, id)
+
+	return k
+}
+
+func decode(value []byte) (*Record, error) {
+	record := &Record{}
+	if err := json.Unmarshal(value, record); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,81 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Parse decodes given JSON, numbers are kept as json.Number so that they
+// keep their original representation
+func Parse(body string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return data, nil
+}
+
+// Lookup returns the value at given dot separated path (e.g. data.users.0.name,
+// array elements are addressed by index), an empty path returns data itself
+func Lookup(data any, path string) (any, bool) {
+	if path == "" {
+		return data, true
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch v := data.(type) {
+		case map[string]any:
+			value, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+
+			data = value
+
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+
+			data = v[index]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return data, true
+}
+
+// Wildcard is the path segment matching every object key and array index in
+// patterns (e.g. data.users.*.email for --redactPath and diff --ignore)
+const Wildcard = "*"
+
+// MatchSegment returns true if given pattern segment matches given path
+// segment (an object key or array index)
+func MatchSegment(pattern string, segment string) bool {
+	return pattern == Wildcard || pattern == segment
+}
+
+// String returns given value as string for comparisons: strings as they are,
+// everything else JSON encoded (e.g. true, 42, null or {"a":1})
+func String(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(encoded)
+}
@@ -0,0 +1,48 @@
+package jsonpath_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/jsonpath"
+)
+
+func TestLookup(t *testing.T) {
+	data, err := jsonpath.Parse(`{"action":"authMethods","data":{"users":[{"name":"jane","age":42}],"active":true,"deleted":null}}`)
+	assert.NoError(t, err)
+
+	value, ok := jsonpath.Lookup(data, "action")
+	assert.True(t, ok)
+	assert.Equal(t, "authMethods", jsonpath.String(value))
+
+	value, ok = jsonpath.Lookup(data, "data.users.0.age")
+	assert.True(t, ok)
+	assert.Equal(t, "42", jsonpath.String(value))
+
+	value, ok = jsonpath.Lookup(data, "data.active")
+	assert.True(t, ok)
+	assert.Equal(t, "true", jsonpath.String(value))
+
+	value, ok = jsonpath.Lookup(data, "data.deleted")
+	assert.True(t, ok)
+	assert.Equal(t, "null", jsonpath.String(value))
+
+	value, ok = jsonpath.Lookup(data, "data.users.0")
+	assert.True(t, ok)
+	assert.Equal(t, `{"age":42,"name":"jane"}`, jsonpath.String(value))
+
+	_, ok = jsonpath.Lookup(data, "data.users.1")
+	assert.False(t, ok)
+
+	_, ok = jsonpath.Lookup(data, "action.length")
+	assert.False(t, ok)
+
+	_, ok = jsonpath.Lookup(data, "unknown")
+	assert.False(t, ok)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := jsonpath.Parse("not json")
+	assert.Error(t, err)
+}
@@ -0,0 +1,207 @@
+package load
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/stats"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// MaxRPS is the highest number of webhook requests started per second, the
+// interval between two webhook requests must not round down to zero
+const MaxRPS = 100000
+
+// Forwarder forwards webhook requests to the local address (implemented by tunnel.Tunnel)
+type Forwarder interface {
+	Forward(req *tunnel.WebhookRequest) (*tunnel.Exchange, error)
+}
+
+// Config defines the load generated
+type Config struct {
+	// RPS is the number of webhook requests started per second
+	RPS int
+
+	// Duration is how long webhook requests are started
+	Duration time.Duration
+
+	// Concurrency is the maximum number of webhook requests in flight, if
+	// reached further webhook requests are skipped (and reported)
+	Concurrency int
+}
+
+// Report is the result of a load test
+type Report struct {
+	Duration      time.Duration
+	Sent          int
+	Skipped       int
+	Errors        int
+	StatusClasses map[string]int
+	Throughput    float64
+	ErrorRate     float64
+	P50Latency    time.Duration
+	P90Latency    time.Duration
+	P95Latency    time.Duration
+	P99Latency    time.Duration
+	MaxLatency    time.Duration
+}
+
+// Validate checks the config
+func (c *Config) Validate() error {
+	if c.RPS < 1 {
+		return errors.New("rps must be at least 1")
+	}
+
+	if c.RPS > MaxRPS {
+		return errors.Errorf("rps must be at most %d", MaxRPS)
+	}
+
+	if c.Duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+
+	if c.Concurrency < 1 {
+		return errors.New("concurrency must be at least 1")
+	}
+
+	return nil
+}
+
+type recorder struct {
+	lock          sync.Mutex
+	errors        int
+	statusClasses map[string]int
+	latencies     []time.Duration
+}
+
+func (r *recorder) record(exchange *tunnel.Exchange, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err != nil {
+		r.errors++
+
+		return
+	}
+
+	r.latencies = append(r.latencies, exchange.Latency)
+	r.statusClasses[stats.StatusClass(exchange.Response.Status)]++
+
+	// Webhook requests must be answered with 2xx, everything else fails at Corbado
+	if exchange.Error != nil || exchange.TimedOut || exchange.Response.Status < http.StatusOK || exchange.Response.Status >= http.StatusMultipleChoices {
+		r.errors++
+	}
+}
+
+// Run replays given recorded webhook requests round-robin with the configured
+// rate until the duration elapsed or given context is done. Templates in path,
+// headers and body (see TemplateData) are executed for every webhook request.
+func Run(ctx context.Context, config *Config, recordings []*tunnel.WebhookRequest, forwarder Forwarder) (*Report, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	templates := make([]*requestTemplate, 0, len(recordings))
+	for _, recording := range recordings {
+		tmpl, err := newRequestTemplate(recording)
+		if err != nil {
+			return nil, err
+		}
+
+		templates = append(templates, tmpl)
+	}
+
+	rec := &recorder{statusClasses: make(map[string]int)}
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < config.Concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for seq := range jobs {
+				req, err := templates[(seq-1)%len(templates)].render(seq)
+				if err != nil {
+					rec.record(nil, err)
+
+					continue
+				}
+
+				rec.record(forwarder.Forward(req))
+			}
+		}()
+	}
+
+	report := &Report{}
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second / time.Duration(config.RPS))
+	defer ticker.Stop()
+
+	seq := 0
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+
+		case <-ticker.C:
+			seq++
+
+			select {
+			case jobs <- seq:
+				report.Sent++
+
+			default:
+				report.Skipped++
+			}
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	rec.summarize(report)
+
+	return report, nil
+}
+
+func (r *recorder) summarize(report *Report) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	report.Errors = r.errors
+	report.StatusClasses = r.statusClasses
+
+	if report.Sent > 0 {
+		report.ErrorRate = float64(r.errors) / float64(report.Sent)
+	}
+
+	if report.Duration > 0 {
+		report.Throughput = float64(len(r.latencies)) / report.Duration.Seconds()
+	}
+
+	if len(r.latencies) == 0 {
+		return
+	}
+
+	sort.Slice(r.latencies, func(i, j int) bool { return r.latencies[i] < r.latencies[j] })
+
+	report.P50Latency = stats.Percentile(r.latencies, 50)
+	report.P90Latency = stats.Percentile(r.latencies, 90)
+	report.P95Latency = stats.Percentile(r.latencies, 95)
+	report.P99Latency = stats.Percentile(r.latencies, 99)
+	report.MaxLatency = r.latencies[len(r.latencies)-1]
+}
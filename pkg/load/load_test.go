@@ -0,0 +1,93 @@
+package load_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/load"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+type forwarderFunc func(req *tunnel.WebhookRequest) (*tunnel.Exchange, error)
+
+func (f forwarderFunc) Forward(req *tunnel.WebhookRequest) (*tunnel.Exchange, error) {
+	return f(req)
+}
+
+func TestLoadRecordings(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte("{\n  \"path\": \"/single\",\n  \"body\": \"{}\"\n}\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.json"), []byte(`[{"path":"/array-1"},{"path":"/array-2"}]`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "c.json"), []byte("{\"path\":\"/line-1\"}\n{\"path\":\"/line-2\"}\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("ignored"), 0o600))
+
+	requests, err := load.LoadRecordings(dir)
+	assert.NoError(t, err)
+
+	paths := make([]string, 0, len(requests))
+	for _, req := range requests {
+		paths = append(paths, req.Path)
+	}
+
+	assert.Equal(t, []string{"/single", "/array-1", "/array-2", "/line-1", "/line-2"}, paths)
+
+	_, err = load.LoadRecordings(filepath.Join(dir, "ignored.txt"))
+	assert.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	var lock sync.Mutex
+	usernames := make(map[string]bool)
+
+	forwarder := forwarderFunc(func(req *tunnel.WebhookRequest) (*tunnel.Exchange, error) {
+		lock.Lock()
+		usernames[req.Body] = true
+		lock.Unlock()
+
+		status := http.StatusOK
+		if req.Headers["X-Fail"] == "true" {
+			status = http.StatusInternalServerError
+		}
+
+		return &tunnel.Exchange{
+			Request:  req,
+			Response: &tunnel.WebhookResponse{ID: req.ID, Status: status},
+			Latency:  time.Millisecond,
+		}, nil
+	})
+
+	recordings := []*tunnel.WebhookRequest{
+		{Path: "/webhook", Body: `{"username":"user-{{.Seq}}@example.com"}`},
+		{Path: "/webhook", Headers: map[string]string{"X-Fail": "true"}, Body: `{"username":"user-{{.Seq}}@example.com"}`},
+	}
+
+	report, err := load.Run(context.Background(), &load.Config{RPS: 100, Duration: 200 * time.Millisecond, Concurrency: 2}, recordings, forwarder)
+	assert.NoError(t, err)
+
+	assert.Greater(t, report.Sent, 5)
+	assert.Len(t, usernames, report.Sent)
+	assert.Equal(t, report.Sent, report.StatusClasses["2xx"]+report.StatusClasses["5xx"])
+	assert.InDelta(t, 0.5, report.ErrorRate, 0.2)
+	assert.Equal(t, time.Millisecond, report.P99Latency)
+	assert.Greater(t, report.Throughput, 0.0)
+}
+
+func TestRunWithInvalidConfig(t *testing.T) {
+	_, err := load.Run(context.Background(), &load.Config{RPS: 0, Duration: time.Second, Concurrency: 1}, nil, nil)
+	assert.EqualError(t, err, "rps must be at least 1")
+
+	_, err = load.Run(context.Background(), &load.Config{RPS: 2000000000, Duration: time.Second, Concurrency: 1}, nil, nil)
+	assert.EqualError(t, err, "rps must be at most 100000")
+}
+
+func TestRunWithInvalidTemplate(t *testing.T) {
+	_, err := load.Run(context.Background(), &load.Config{RPS: 1, Duration: time.Second, Concurrency: 1}, []*tunnel.WebhookRequest{{Path: "/{{.Seq"}}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package load
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// LoadRecordings loads recorded webhook requests from given file or
+// directory (all *.json files). A file contains a single webhook request, a
+// JSON array of webhook requests or one webhook request per line (JSON Lines).
+func LoadRecordings(path string) ([]*tunnel.WebhookRequest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		sort.Strings(files)
+	}
+
+	var requests []*tunnel.WebhookRequest
+	for _, file := range files {
+		fileRequests, err := loadFile(file)
+		if err != nil {
+			return nil, errors.Errorf("%s: %s", file, err.Error())
+		}
+
+		requests = append(requests, fileRequests...)
+	}
+
+	if len(requests) == 0 {
+		return nil, errors.Errorf("no webhook requests found in %s", path)
+	}
+
+	return requests, nil
+}
+
+func loadFile(file string) ([]*tunnel.WebhookRequest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		var requests []*tunnel.WebhookRequest
+		if err := json.Unmarshal(data, &requests); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return validate(requests)
+	}
+
+	var requests []*tunnel.WebhookRequest
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		req := &tunnel.WebhookRequest{}
+		if err := json.Unmarshal([]byte(line), req); err != nil {
+			// Not JSON Lines, maybe a single pretty printed webhook request
+			req = &tunnel.WebhookRequest{}
+			if err := json.Unmarshal(data, req); err != nil {
+				return nil, errors.WithStack(err)
+			}
+
+			return validate([]*tunnel.WebhookRequest{req})
+		}
+
+		requests = append(requests, req)
+	}
+
+	return validate(requests)
+}
+
+func validate(requests []*tunnel.WebhookRequest) ([]*tunnel.WebhookRequest, error) {
+	for i, req := range requests {
+		if req.Path == "" {
+			return nil, errors.Errorf("webhook request %d has no path", i+1)
+		}
+	}
+
+	return requests, nil
+}
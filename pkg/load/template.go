@@ -0,0 +1,127 @@
+package load
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// TemplateData is available in templated webhook requests, e.g.
+// {"username":"user-{{.Seq}}@example.com","id":"{{.UUID}}"}
+type TemplateData struct {
+	// Seq is the sequence number of the webhook request (starting at 1)
+	Seq int
+
+	// UUID is a random UUID (version 4)
+	UUID string
+
+	// Unix is the current Unix timestamp
+	Unix int64
+}
+
+// requestTemplate renders a recorded webhook request with unique values
+type requestTemplate struct {
+	recording *tunnel.WebhookRequest
+	path      *template.Template
+	headers   map[string]*template.Template
+	body      *template.Template
+}
+
+func newRequestTemplate(recording *tunnel.WebhookRequest) (*requestTemplate, error) {
+	t := &requestTemplate{
+		recording: recording,
+		headers:   make(map[string]*template.Template, len(recording.Headers)),
+	}
+
+	var err error
+	if t.path, err = parseTemplate(recording.Path); err != nil {
+		return nil, err
+	}
+
+	if t.body, err = parseTemplate(recording.Body); err != nil {
+		return nil, err
+	}
+
+	for name, value := range recording.Headers {
+		if t.headers[name], err = parseTemplate(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	if !strings.Contains(text, "{{") {
+		return nil, nil
+	}
+
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return tmpl, nil
+}
+
+// render returns the webhook request with all templates executed
+func (t *requestTemplate) render(seq int) (*tunnel.WebhookRequest, error) {
+	data := &TemplateData{
+		Seq:  seq,
+		UUID: newUUID(),
+		Unix: time.Now().Unix(),
+	}
+
+	req := &tunnel.WebhookRequest{
+		ID:      fmt.Sprintf("load-%d", seq),
+		Headers: make(map[string]string, len(t.recording.Headers)),
+	}
+
+	var err error
+	if req.Path, err = execute(t.path, t.recording.Path, data); err != nil {
+		return nil, err
+	}
+
+	if req.Body, err = execute(t.body, t.recording.Body, data); err != nil {
+		return nil, err
+	}
+
+	for name, value := range t.recording.Headers {
+		if req.Headers[name], err = execute(t.headers[name], value, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+func execute(tmpl *template.Template, text string, data *TemplateData) (string, error) {
+	if tmpl == nil {
+		return text, nil
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return b.String(), nil
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,130 @@
+package match
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/jsonpath"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+const (
+	fieldPath    = "path"
+	fieldBody    = "body"
+	fieldHeaders = "headers"
+)
+
+// Matcher checks a single condition of a webhook request
+type Matcher struct {
+	field  string
+	key    string
+	value  string
+	negate bool
+}
+
+// Parse parses given expression of the form <field>=<value> or
+// <field>!=<value> where field is path, body.<JSON path> (e.g.
+// body.data.username) or headers.<name> (case-insensitive)
+func Parse(expression string) (*Matcher, error) {
+	negate := false
+	name, value, found := strings.Cut(expression, "!=")
+	if found {
+		negate = true
+	} else {
+		name, value, found = strings.Cut(expression, "=")
+		if !found {
+			return nil, errors.Errorf("invalid match expression '%s' (must be <field>=<value>)", expression)
+		}
+	}
+
+	name = strings.TrimSpace(name)
+	field, key, _ := strings.Cut(name, ".")
+
+	switch field {
+	case fieldPath:
+		if key != "" {
+			return nil, errors.Errorf("invalid match expression '%s' (path has no subfields)", expression)
+		}
+
+	case fieldBody:
+
+	case fieldHeaders:
+		if key == "" {
+			return nil, errors.Errorf("invalid match expression '%s' (header name is missing)", expression)
+		}
+
+	default:
+		return nil, errors.Errorf("invalid match expression '%s' (field must be path, body.<JSON path> or headers.<name>)", expression)
+	}
+
+	return &Matcher{
+		field:  field,
+		key:    key,
+		value:  value,
+		negate: negate,
+	}, nil
+}
+
+// ParseAll parses all given expressions
+func ParseAll(expressions []string) ([]*Matcher, error) {
+	matchers := make([]*Matcher, 0, len(expressions))
+	for _, expression := range expressions {
+		matcher, err := Parse(expression)
+		if err != nil {
+			return nil, err
+		}
+
+		matchers = append(matchers, matcher)
+	}
+
+	return matchers, nil
+}
+
+// Match returns true if given webhook request matches
+func (m *Matcher) Match(req *tunnel.WebhookRequest) bool {
+	actual, ok := m.lookup(req)
+
+	return (ok && actual == m.value) != m.negate
+}
+
+// MatchAll returns true if given webhook request matches all given matchers
+func MatchAll(matchers []*Matcher, req *tunnel.WebhookRequest) bool {
+	for _, matcher := range matchers {
+		if !matcher.Match(req) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *Matcher) lookup(req *tunnel.WebhookRequest) (string, bool) {
+	switch m.field {
+	case fieldPath:
+		return req.Path, true
+
+	case fieldHeaders:
+		for name, value := range req.Headers {
+			if http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(m.key) {
+				return value, true
+			}
+		}
+
+		return "", false
+
+	default:
+		data, err := jsonpath.Parse(req.Body)
+		if err != nil {
+			return "", false
+		}
+
+		value, ok := jsonpath.Lookup(data, m.key)
+		if !ok {
+			return "", false
+		}
+
+		return jsonpath.String(value), true
+	}
+}
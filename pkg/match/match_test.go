@@ -0,0 +1,62 @@
+package match_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/match"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func webhookRequest() *tunnel.WebhookRequest {
+	return &tunnel.WebhookRequest{
+		ID:      "who-1",
+		Path:    "/webhook",
+		Headers: map[string]string{"X-Corbado-Event": "userCreated"},
+		Body:    `{"action":"userCreated","data":{"userID":42,"verified":true}}`,
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{"path=/webhook", true},
+		{"path=/other", false},
+		{"path!=/other", true},
+		{"body.action=userCreated", true},
+		{"body.action=userDeleted", false},
+		{"body.data.userID=42", true},
+		{"body.data.verified=true", true},
+		{"body.data.unknown=x", false},
+		{"body.data.unknown!=x", true},
+		{"headers.x-corbado-event=userCreated", true},
+		{"headers.X-Other=userCreated", false},
+		{"body.action=", false},
+	}
+
+	for _, test := range tests {
+		matcher, err := match.Parse(test.expression)
+		assert.NoError(t, err, test.expression)
+		assert.Equal(t, test.expected, matcher.Match(webhookRequest()), test.expression)
+	}
+}
+
+func TestMatchAll(t *testing.T) {
+	matchers, err := match.ParseAll([]string{"path=/webhook", "body.action=userCreated"})
+	assert.NoError(t, err)
+	assert.True(t, match.MatchAll(matchers, webhookRequest()))
+
+	matchers, err = match.ParseAll([]string{"path=/webhook", "body.action=userDeleted"})
+	assert.NoError(t, err)
+	assert.False(t, match.MatchAll(matchers, webhookRequest()))
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, expression := range []string{"body.action", "unknown=x", "path.x=y", "headers=x"} {
+		_, err := match.Parse(expression)
+		assert.Error(t, err, expression)
+	}
+}
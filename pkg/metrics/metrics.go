@@ -0,0 +1,198 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+type requestKey struct {
+	path   string
+	status int
+}
+
+type Metrics struct {
+	status  tunnel.StatusSource
+	buckets []float64
+
+	lock          sync.Mutex
+	requests      map[requestKey]int
+	timeouts      int
+	errors        int
+	bucketCounts  []int
+	latencyCount  int
+	latencySum    float64
+	requestBytes  int
+	responseBytes int
+}
+
+// DefaultBuckets returns the default latency histogram buckets (in seconds)
+func DefaultBuckets() []float64 {
+	return []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+}
+
+// New returns new metrics instance, the tunnel connection metrics are read
+// from given status source when scraped
+func New(status tunnel.StatusSource) *Metrics {
+	buckets := DefaultBuckets()
+
+	return &Metrics{
+		status:       status,
+		buckets:      buckets,
+		requests:     make(map[requestKey]int),
+		bucketCounts: make([]int, len(buckets)),
+	}
+}
+
+// Observe records given exchange (implements tunnel.Observer)
+func (m *Metrics) Observe(exchange *tunnel.Exchange) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	status := 0
+	if exchange.Response != nil {
+		status = exchange.Response.Status
+		m.responseBytes += len(exchange.Response.Body)
+	}
+
+	m.requests[requestKey{path: exchange.Request.Path, status: status}]++
+	m.requestBytes += len(exchange.Request.Body)
+
+	if exchange.TimedOut {
+		m.timeouts++
+	}
+
+	if exchange.Error != nil {
+		m.errors++
+	}
+
+	seconds := exchange.Latency.Seconds()
+	for i, bucket := range m.buckets {
+		if seconds <= bucket {
+			m.bucketCounts[i]++
+		}
+	}
+
+	m.latencyCount++
+	m.latencySum += seconds
+}
+
+// ServeHTTP writes all metrics in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	if err := m.Write(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Write writes all metrics in the Prometheus text format to given writer
+func (m *Metrics) Write(w io.Writer) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	b := &strings.Builder{}
+
+	writeHeader(b, "corbado_webhook_requests_total", "counter", "Webhook requests forwarded to the local address")
+	keys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].path != keys[j].path {
+			return keys[i].path < keys[j].path
+		}
+
+		return keys[i].status < keys[j].status
+	})
+
+	for _, key := range keys {
+		fmt.Fprintf(b, "corbado_webhook_requests_total{path=%s,status=\"%d\"} %d\n", quote(key.path), key.status, m.requests[key])
+	}
+
+	writeHeader(b, "corbado_webhook_timeouts_total", "counter", "Webhook requests the local address did not answer in time")
+	fmt.Fprintf(b, "corbado_webhook_timeouts_total %d\n", m.timeouts)
+
+	writeHeader(b, "corbado_webhook_errors_total", "counter", "Webhook requests that could not be forwarded to the local address")
+	fmt.Fprintf(b, "corbado_webhook_errors_total %d\n", m.errors)
+
+	writeHeader(b, "corbado_webhook_request_bytes_total", "counter", "Body bytes received from Corbado")
+	fmt.Fprintf(b, "corbado_webhook_request_bytes_total %d\n", m.requestBytes)
+
+	writeHeader(b, "corbado_webhook_response_bytes_total", "counter", "Body bytes sent back to Corbado")
+	fmt.Fprintf(b, "corbado_webhook_response_bytes_total %d\n", m.responseBytes)
+
+	writeHeader(b, "corbado_local_latency_seconds", "histogram", "Time the local address needed to answer")
+	for i, bucket := range m.buckets {
+		fmt.Fprintf(b, "corbado_local_latency_seconds_bucket{le=\"%s\"} %d\n", formatFloat(bucket), m.bucketCounts[i])
+	}
+
+	fmt.Fprintf(b, "corbado_local_latency_seconds_bucket{le=\"+Inf\"} %d\n", m.latencyCount)
+	fmt.Fprintf(b, "corbado_local_latency_seconds_sum %s\n", formatFloat(m.latencySum))
+	fmt.Fprintf(b, "corbado_local_latency_seconds_count %d\n", m.latencyCount)
+
+	if m.status != nil {
+		status := m.status()
+
+		writeHeader(b, "corbado_tunnel_connected", "gauge", "Whether the tunnel is connected to the tunnel server")
+		fmt.Fprintf(b, "corbado_tunnel_connected %d\n", boolToInt(status.Connected))
+
+		writeHeader(b, "corbado_tunnel_reconnects_total", "counter", "Reconnects to the tunnel server")
+		fmt.Fprintf(b, "corbado_tunnel_reconnects_total %d\n", status.Reconnects)
+
+		writeHeader(b, "corbado_tunnel_websocket_errors_total", "counter", "Errors reading from or writing to the tunnel server")
+		fmt.Fprintf(b, "corbado_tunnel_websocket_errors_total %d\n", status.WebsocketErrors)
+
+		writeHeader(b, "corbado_tunnel_last_message_timestamp_seconds", "gauge", "Unix time of the last message received from the tunnel server")
+		fmt.Fprintf(b, "corbado_tunnel_last_message_timestamp_seconds %d\n", unix(status.LastMessage))
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func writeHeader(b *strings.Builder, name string, metricType string, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, metricType)
+}
+
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+
+	return `"` + value + `"`
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}
+
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
@@ -0,0 +1,64 @@
+package metrics_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/metrics"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New(func() tunnel.Status {
+		return tunnel.Status{
+			Connected:       true,
+			Reconnects:      2,
+			WebsocketErrors: 3,
+		}
+	})
+
+	m.Observe(&tunnel.Exchange{
+		Request:  &tunnel.WebhookRequest{Path: "/webhook", Body: "1234"},
+		Response: &tunnel.WebhookResponse{Status: 200, Body: "12"},
+		Latency:  20 * time.Millisecond,
+	})
+	m.Observe(&tunnel.Exchange{
+		Request:  &tunnel.WebhookRequest{Path: "/webhook", Body: "1234"},
+		Response: &tunnel.WebhookResponse{Status: 504},
+		Latency:  10 * time.Second,
+		TimedOut: true,
+	})
+	m.Observe(&tunnel.Exchange{
+		Request:  &tunnel.WebhookRequest{Path: `/we"b`},
+		Response: &tunnel.WebhookResponse{Status: 500},
+		Error:    errors.New("connection refused"),
+	})
+
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := recorder.Body.String()
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, body, "# TYPE corbado_webhook_requests_total counter\n")
+	assert.Contains(t, body, "corbado_webhook_requests_total{path=\"/webhook\",status=\"200\"} 1\n")
+	assert.Contains(t, body, "corbado_webhook_requests_total{path=\"/webhook\",status=\"504\"} 1\n")
+	assert.Contains(t, body, "corbado_webhook_requests_total{path=\"/we\\\"b\",status=\"500\"} 1\n")
+	assert.Contains(t, body, "corbado_webhook_timeouts_total 1\n")
+	assert.Contains(t, body, "corbado_webhook_errors_total 1\n")
+	assert.Contains(t, body, "corbado_webhook_request_bytes_total 8\n")
+	assert.Contains(t, body, "corbado_webhook_response_bytes_total 2\n")
+	assert.Contains(t, body, "corbado_local_latency_seconds_bucket{le=\"0.01\"} 1\n")
+	assert.Contains(t, body, "corbado_local_latency_seconds_bucket{le=\"0.025\"} 2\n")
+	assert.Contains(t, body, "corbado_local_latency_seconds_bucket{le=\"10\"} 3\n")
+	assert.Contains(t, body, "corbado_local_latency_seconds_bucket{le=\"+Inf\"} 3\n")
+	assert.Contains(t, body, "corbado_local_latency_seconds_count 3\n")
+	assert.Contains(t, body, "corbado_tunnel_connected 1\n")
+	assert.Contains(t, body, "corbado_tunnel_reconnects_total 2\n")
+	assert.Contains(t, body, "corbado_tunnel_websocket_errors_total 3\n")
+}
@@ -0,0 +1,146 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/corbado/cli/pkg/jsonpath"
+)
+
+// Mask replaces every redacted value
+const Mask = "[REDACTED]"
+
+// DefaultHeaders returns the headers that are always redacted
+func DefaultHeaders() []string {
+	return []string{"Authorization", "Cookie", "Set-Cookie"}
+}
+
+// DefaultPaths returns the JSON body paths that are always redacted (the
+// password of passwordVerify webhook requests)
+func DefaultPaths() []string {
+	return []string{"data.password"}
+}
+
+type Redactor struct {
+	headers map[string]struct{}
+	paths   [][]string
+}
+
+// New returns new redactor instance which masks the default headers and
+// paths, the given headers (case-insensitive) and the given JSON paths. A
+// JSON path is a dot separated list of object keys and array indices (e.g.
+// data.username or data.users.0.email), "*" matches every key and index.
+func New(headers []string, paths []string) *Redactor {
+	r := &Redactor{
+		headers: make(map[string]struct{}),
+	}
+
+	for _, header := range append(DefaultHeaders(), headers...) {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+
+		r.headers[http.CanonicalHeaderKey(header)] = struct{}{}
+	}
+
+	for _, path := range append(DefaultPaths(), paths...) {
+		path = strings.Trim(strings.TrimSpace(path), ".")
+		if path == "" {
+			continue
+		}
+
+		r.paths = append(r.paths, strings.Split(path, "."))
+	}
+
+	return r
+}
+
+// Headers returns a copy of given headers with all configured headers masked
+func (r *Redactor) Headers(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if r.IsRedactedHeader(name) {
+			value = Mask
+		}
+
+		result[name] = value
+	}
+
+	return result
+}
+
+// IsRedactedHeader returns true if given header is masked
+func (r *Redactor) IsRedactedHeader(name string) bool {
+	_, ok := r.headers[http.CanonicalHeaderKey(name)]
+
+	return ok
+}
+
+// Body returns given body with all configured JSON paths masked. Bodies which
+// are not JSON are returned unchanged.
+func (r *Redactor) Body(body string) string {
+	if len(r.paths) == 0 || body == "" {
+		return body
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return body
+	}
+
+	changed := false
+	for _, path := range r.paths {
+		data = redactPath(data, path, &changed)
+	}
+
+	if !changed {
+		return body
+	}
+
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(data); err != nil {
+		return body
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+func redactPath(data any, path []string, changed *bool) any {
+	if len(path) == 0 {
+		*changed = true
+
+		return Mask
+	}
+
+	switch v := data.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if jsonpath.MatchSegment(path[0], key) {
+				v[key] = redactPath(value, path[1:], changed)
+			}
+		}
+
+	case []any:
+		for i, value := range v {
+			if jsonpath.MatchSegment(path[0], strconv.Itoa(i)) {
+				v[i] = redactPath(value, path[1:], changed)
+			}
+		}
+	}
+
+	return data
+}
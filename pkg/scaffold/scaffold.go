@@ -0,0 +1,177 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	TemplateGo     = "go"
+	TemplateNode   = "node"
+	TemplatePython = "python"
+)
+
+//go:embed templates
+var templateFiles embed.FS
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Options defines the generated project
+type Options struct {
+	// Template is the language and framework: go (net/http), node (Express) or python (Flask)
+	Template string
+
+	// Name is the project name (Go module and package name)
+	Name string
+
+	// ProjectID is the ID of the Corbado project
+	ProjectID string
+
+	// Port is the port the webhook handler listens on
+	Port int
+}
+
+type templateData struct {
+	*Options
+	LocalAddress string
+	RunCommand   string
+	DepsCommand  string
+}
+
+// File is a generated file with a path relative to the project directory
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Templates returns the names of all templates
+func Templates() []string {
+	return []string{TemplateGo, TemplateNode, TemplatePython}
+}
+
+// ValidateTemplate checks if a template with given name exists
+func ValidateTemplate(name string) error {
+	for _, template := range Templates() {
+		if template == name {
+			return nil
+		}
+	}
+
+	return errors.Errorf("unknown template '%s' (allowed: %s)", name, strings.Join(Templates(), ", "))
+}
+
+// ValidateName checks if given project name can be used as module and package name
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return errors.Errorf("invalid name '%s' (lowercase letters, digits, dots, dashes and underscores only)", name)
+	}
+
+	return nil
+}
+
+// Render returns all files of the project: the webhook handler of the
+// template, corbado.yaml and a Makefile
+func Render(options *Options) ([]*File, error) {
+	if err := ValidateTemplate(options.Template); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateName(options.Name); err != nil {
+		return nil, err
+	}
+
+	data := &templateData{
+		Options:      options,
+		LocalAddress: "http://localhost:" + strconv.Itoa(options.Port),
+	}
+
+	switch options.Template {
+	case TemplateGo:
+		data.RunCommand = "go run ."
+		data.DepsCommand = "go mod tidy"
+
+	case TemplateNode:
+		data.RunCommand = "node index.js"
+		data.DepsCommand = "npm install"
+
+	case TemplatePython:
+		data.RunCommand = "python3 app.py"
+		data.DepsCommand = "pip3 install -r requirements.txt"
+	}
+
+	var files []*File
+
+	for _, dir := range []string{"templates/common", "templates/" + options.Template} {
+		entries, err := fs.ReadDir(templateFiles, dir)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		for _, entry := range entries {
+			file, err := render(path.Join(dir, entry.Name()), data)
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+func render(name string, data *templateData) (*File, error) {
+	text, err := templateFiles.ReadFile(name)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	tmpl, err := template.New(path.Base(name)).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, data); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &File{
+		Path:    strings.TrimSuffix(path.Base(name), ".tmpl"),
+		Content: content.Bytes(),
+	}, nil
+}
+
+// Write writes given files to the directory, existing files are only
+// overwritten if force is true
+func Write(dir string, files []*File, force bool) error {
+	if !force {
+		for _, file := range files {
+			if _, err := os.Stat(filepath.Join(dir, file.Path)); err == nil {
+				return errors.Errorf("%s already exists (use force to overwrite)", filepath.Join(dir, file.Path))
+			}
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file.Path), file.Content, 0o644); err != nil { //nolint:gosec // source files of the project, readable like any other
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package scaffold_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/scaffold"
+	"github.com/corbado/cli/pkg/testsuite"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func render(t *testing.T, template string, port int) map[string]string {
+	files, err := scaffold.Render(&scaffold.Options{Template: template, Name: "my-webhooks", ProjectID: "pro-1", Port: port})
+	assert.NoError(t, err)
+
+	contents := map[string]string{}
+	for _, file := range files {
+		contents[file.Path] = string(file.Content)
+	}
+
+	return contents
+}
+
+func TestRender(t *testing.T) {
+	expected := map[string][]string{
+		scaffold.TemplateGo:     {"Makefile", "corbado.yaml", "go.mod", "main.go"},
+		scaffold.TemplateNode:   {"Makefile", "corbado.yaml", "index.js", "package.json"},
+		scaffold.TemplatePython: {"Makefile", "corbado.yaml", "app.py", "requirements.txt"},
+	}
+
+	for template, paths := range expected {
+		files := render(t, template, 3000)
+
+		for _, path := range paths {
+			assert.Contains(t, files, path, template)
+		}
+
+		assert.Len(t, files, len(paths), template)
+		assert.Contains(t, files["Makefile"], "LOCAL_ADDRESS ?= http://localhost:3000\n")
+		assert.Contains(t, files["Makefile"], "test:\n\tcorbado test --exec \"")
+		assert.Contains(t, files["corbado.yaml"], "  projectID: pro-1\n")
+
+		suite, err := testsuite.Parse([]byte(files["corbado.yaml"]))
+		assert.NoError(t, err)
+		assert.Len(t, suite.Tests, 4)
+	}
+
+	assert.Contains(t, render(t, scaffold.TemplateGo, 3000)["go.mod"], "module my-webhooks\n")
+	assert.Contains(t, render(t, scaffold.TemplateNode, 3000)["package.json"], `"name": "my-webhooks"`)
+}
+
+func TestRenderWithInvalidOptions(t *testing.T) {
+	_, err := scaffold.Render(&scaffold.Options{Template: "rust", Name: "a", Port: 8000})
+	assert.EqualError(t, err, "unknown template 'rust' (allowed: go, node, python)")
+
+	_, err = scaffold.Render(&scaffold.Options{Template: scaffold.TemplateGo, Name: "My Webhooks", Port: 8000})
+	assert.EqualError(t, err, "invalid name 'My Webhooks' (lowercase letters, digits, dots, dashes and underscores only)")
+}
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "project")
+	files := []*scaffold.File{{Path: "Makefile", Content: []byte("run:\n")}}
+
+	assert.NoError(t, scaffold.Write(dir, files, false))
+	assert.EqualError(t, scaffold.Write(dir, files, false), filepath.Join(dir, "Makefile")+" already exists (use force to overwrite)")
+	assert.NoError(t, scaffold.Write(dir, files, true))
+}
+
+// TestGoProjectPassesSuite builds the generated Go project and runs the test
+// suite of its corbado.yaml against it
+func TestGoProjectPassesSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a Go project")
+	}
+
+	goBinary, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	assert.NoError(t, listener.Close())
+
+	dir := t.TempDir()
+	files, err := scaffold.Render(&scaffold.Options{Template: scaffold.TemplateGo, Name: "my-webhooks", ProjectID: "pro-1", Port: port})
+	assert.NoError(t, err)
+	assert.NoError(t, scaffold.Write(dir, files, false))
+
+	build := exec.Command(goBinary, "build", "-o", "server", ".")
+	build.Dir = dir
+	build.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+
+	output, err := build.CombinedOutput()
+	if !assert.NoError(t, err, string(output)) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := exec.CommandContext(ctx, filepath.Join(dir, "server"))
+	assert.NoError(t, server.Start())
+
+	defer func() {
+		cancel()
+		_ = server.Wait()
+	}()
+
+	localAddress := fmt.Sprintf("http://127.0.0.1:%d", port)
+	waitForServer(t, localAddress)
+
+	suite, err := testsuite.Load(filepath.Join(dir, "corbado.yaml"))
+	assert.NoError(t, err)
+
+	tun := tunnel.New(ansi.New(false, io.Discard), "")
+	tun.SetLocalAddress(localAddress)
+	tun.SetQuiet(true)
+
+	for _, result := range testsuite.Run(suite, tun, nil) {
+		assert.True(t, result.Passed(), "%s: %v %v", result.Test.Name, result.Failures, result.Error)
+	}
+}
+
+func waitForServer(t *testing.T, address string) {
+	deadline := time.Now().Add(10 * time.Second)
+
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(address) //nolint:gosec,noctx
+		if err == nil {
+			_ = resp.Body.Close()
+
+			return
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	t.Fatalf("%s not reachable", address)
+}
+
+func TestNodeAndPythonProjectsAreValid(t *testing.T) {
+	dir := t.TempDir()
+
+	if node, err := exec.LookPath("node"); err == nil {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "index.js"), []byte(render(t, scaffold.TemplateNode, 8000)["index.js"]), 0o600))
+
+		output, err := exec.Command(node, "--check", filepath.Join(dir, "index.js")).CombinedOutput()
+		assert.NoError(t, err, string(output))
+	}
+
+	if python, err := exec.LookPath("python3"); err == nil {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.py"), []byte(render(t, scaffold.TemplatePython, 8000)["app.py"]), 0o600))
+
+		output, err := exec.Command(python, "-m", "py_compile", filepath.Join(dir, "app.py")).CombinedOutput()
+		assert.NoError(t, err, string(output))
+	}
+}
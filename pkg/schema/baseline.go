@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// requiredSamples is the number of payloads a baseline needs before missing
+// properties are reported, a property is required if all of them had it
+const requiredSamples = 5
+
+// Baseline holds the schemas of all webhook payloads seen so far (keyed by
+// path and event) and persists them to a JSON file
+type Baseline struct {
+	file string
+
+	lock    sync.Mutex
+	entries map[string]*entry
+}
+
+// entry is the schema of one kind of payload and how many payloads it was
+// inferred from (counted up to requiredSamples)
+type entry struct {
+	Samples int     `json:"samples"`
+	Schema  *Schema `json:"schema"`
+}
+
+// LoadBaseline returns the baseline stored in given file, it is empty if the
+// file does not exist yet
+func LoadBaseline(file string) (*Baseline, error) {
+	b := &Baseline{
+		file:    file,
+		entries: map[string]*entry{},
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return b, nil
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	if err := json.Unmarshal(data, &b.entries); err != nil {
+		return nil, errors.Errorf("%s: %s", file, err.Error())
+	}
+
+	return b, nil
+}
+
+// Schema returns the baseline schema for given key (nil if there is none)
+func (b *Baseline) Schema(key string) *Schema {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	e, ok := b.entries[key]
+	if !ok {
+		return nil
+	}
+
+	return e.Schema
+}
+
+// Check compares given payload to the baseline schema for given key. The
+// payload becomes part of the baseline if the key is new, it does not drift
+// or accept is true. Missing properties are only reported once the baseline
+// has requiredSamples payloads, a single payload does not tell which
+// properties are optional. Changes are persisted immediately.
+func (b *Baseline) Check(key string, payload any, accept bool) ([]Drift, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	observed := Infer(payload)
+
+	e, ok := b.entries[key]
+	if !ok || e.Schema == nil {
+		b.entries[key] = &entry{Samples: 1, Schema: observed}
+
+		return nil, b.save()
+	}
+
+	drifts := Compare(e.Schema, observed)
+	if e.Samples < requiredSamples {
+		drifts = withoutRemoved(drifts)
+	}
+
+	if len(drifts) > 0 && !accept {
+		return drifts, nil
+	}
+
+	merged := Merge(e.Schema, observed)
+	if equal(e.Schema, merged) && e.Samples >= requiredSamples {
+		return drifts, nil
+	}
+
+	e.Schema = merged
+	if e.Samples < requiredSamples {
+		e.Samples++
+	}
+
+	return drifts, b.save()
+}
+
+func (b *Baseline) save() error {
+	data, err := json.MarshalIndent(b.entries, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.file), 0o700); err != nil {
+		return errors.WithStack(err)
+	}
+
+	// Write and rename so that the baseline is never left half written
+	tmpFile := b.file + ".tmp"
+	if err := os.WriteFile(tmpFile, append(data, '\n'), 0o600); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(os.Rename(tmpFile, b.file))
+}
+
+func equal(a *Schema, b *Schema) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+
+	return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
+
+func withoutRemoved(drifts []Drift) []Drift {
+	var result []Drift
+
+	for _, drift := range drifts {
+		if drift.Kind != DriftRemoved {
+			result = append(result, drift)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,102 @@
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+//go:embed corbado/*.json
+var catalogFiles embed.FS
+
+// Action describes a Corbado webhook action with the schemas of the webhook
+// request Corbado sends and of the response it expects
+type Action struct {
+	Name     string
+	Request  *Schema
+	Response *Schema
+}
+
+// Actions returns all Corbado webhook actions of the embedded catalog,
+// ordered by name
+func Actions() ([]*Action, error) {
+	entries, err := catalogFiles.ReadDir("corbado")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	actions := map[string]*Action{}
+
+	for _, entry := range entries {
+		parts := strings.Split(entry.Name(), ".")
+		if len(parts) != 3 {
+			return nil, errors.Errorf("invalid catalog file name %s", entry.Name())
+		}
+
+		schema, err := loadCatalogFile(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		action, ok := actions[parts[0]]
+		if !ok {
+			action = &Action{Name: parts[0]}
+			actions[parts[0]] = action
+		}
+
+		switch parts[1] {
+		case "request":
+			action.Request = schema
+
+		case "response":
+			action.Response = schema
+
+		default:
+			return nil, errors.Errorf("invalid catalog file name %s", entry.Name())
+		}
+	}
+
+	result := make([]*Action, 0, len(actions))
+	for _, action := range actions {
+		result = append(result, action)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// LookupAction returns the Corbado webhook action with given name
+func LookupAction(name string) (*Action, bool) {
+	actions, err := Actions()
+	if err != nil {
+		return nil, false
+	}
+
+	for _, action := range actions {
+		if action.Name == name {
+			return action, true
+		}
+	}
+
+	return nil, false
+}
+
+func loadCatalogFile(name string) (*Schema, error) {
+	data, err := catalogFiles.ReadFile("corbado/" + name)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	schema := &Schema{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, errors.Errorf("invalid catalog file %s: %s", name, err.Error())
+	}
+
+	return schema, nil
+}
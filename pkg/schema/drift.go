@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"sort"
+	"strings"
+)
+
+type DriftKind string
+
+const (
+	DriftAdded       DriftKind = "+"
+	DriftRemoved     DriftKind = "-"
+	DriftTypeChanged DriftKind = "~"
+)
+
+// Drift is a difference of an observed payload to the baseline schema at a
+// dot separated path (array items are addressed by *, e.g. data.users.*.name)
+type Drift struct {
+	Kind DriftKind
+	Path string
+	Old  Types
+	New  Types
+}
+
+// String returns a single line description (e.g. "~ data.age: string -> number")
+func (d Drift) String() string {
+	switch d.Kind {
+	case DriftAdded:
+		return "+ " + d.Path + ": " + strings.Join(d.New, "|")
+
+	case DriftRemoved:
+		return "- " + d.Path + ": " + strings.Join(d.Old, "|")
+
+	default:
+		return "~ " + d.Path + ": " + strings.Join(d.Old, "|") + " -> " + strings.Join(d.New, "|")
+	}
+}
+
+// Compare returns how an observed schema drifts from the baseline: added
+// properties, missing required properties and types the baseline does not allow
+func Compare(baseline *Schema, observed *Schema) []Drift {
+	var drifts []Drift
+
+	compare(nil, baseline, observed, &drifts)
+
+	return drifts
+}
+
+func compare(segments []string, baseline *Schema, observed *Schema, drifts *[]Drift) {
+	for _, typ := range observed.Type {
+		if !baseline.Type.contains(typ) {
+			*drifts = append(*drifts, Drift{Kind: DriftTypeChanged, Path: strings.Join(segments, "."), Old: baseline.Type, New: observed.Type})
+
+			break
+		}
+	}
+
+	if baseline.Type.contains(TypeObject) && observed.Type.contains(TypeObject) {
+		compareProperties(segments, baseline, observed, drifts)
+	}
+
+	if baseline.Items != nil && observed.Items != nil {
+		compare(appendSegment(segments, "*"), baseline.Items, observed.Items, drifts)
+	}
+}
+
+func compareProperties(segments []string, baseline *Schema, observed *Schema, drifts *[]Drift) {
+	keys := make([]string, 0, len(observed.Properties))
+	for key := range observed.Properties {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		property, ok := baseline.Properties[key]
+		if !ok {
+			*drifts = append(*drifts, Drift{Kind: DriftAdded, Path: strings.Join(appendSegment(segments, key), "."), New: observed.Properties[key].Type})
+
+			continue
+		}
+
+		compare(appendSegment(segments, key), property, observed.Properties[key], drifts)
+	}
+
+	for _, key := range baseline.Required {
+		if _, ok := observed.Properties[key]; ok {
+			continue
+		}
+
+		drift := Drift{Kind: DriftRemoved, Path: strings.Join(appendSegment(segments, key), ".")}
+		if property, ok := baseline.Properties[key]; ok && property != nil {
+			drift.Old = property.Type
+		}
+
+		*drifts = append(*drifts, drift)
+	}
+}
+
+func appendSegment(segments []string, segment string) []string {
+	result := make([]string, len(segments), len(segments)+1)
+	copy(result, segments)
+
+	return append(result, segment)
+}
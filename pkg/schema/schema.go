@@ -0,0 +1,187 @@
+package schema
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+	TypeNull    = "null"
+)
+
+// Schema is the subset of JSON Schema needed to describe webhook payloads
+type Schema struct {
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        Types              `json:"type,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+}
+
+// Types are the allowed JSON types of a value, encoded as string if there
+// is only one (like JSON Schema does)
+type Types []string
+
+// MarshalJSON implements json.Marshaler
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return errors.WithStack(err)
+	}
+
+	*t = multiple
+
+	return nil
+}
+
+func (t Types) contains(typ string) bool {
+	for _, existing := range t {
+		if existing == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TypeOf returns the JSON type of a decoded JSON value
+func TypeOf(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return TypeObject
+
+	case []any:
+		return TypeArray
+
+	case string:
+		return TypeString
+
+	case bool:
+		return TypeBoolean
+
+	case nil:
+		return TypeNull
+
+	default:
+		return TypeNumber
+	}
+}
+
+// Infer returns the schema of a decoded JSON value, every property of an
+// object is required (merge schemas of several values to find optional ones)
+func Infer(value any) *Schema {
+	schema := &Schema{Type: Types{TypeOf(value)}}
+
+	switch v := value.(type) {
+	case map[string]any:
+		schema.Properties = make(map[string]*Schema, len(v))
+		schema.Required = make([]string, 0, len(v))
+
+		for key, property := range v {
+			schema.Properties[key] = Infer(property)
+			schema.Required = append(schema.Required, key)
+		}
+
+		sort.Strings(schema.Required)
+
+	case []any:
+		for _, item := range v {
+			schema.Items = Merge(schema.Items, Infer(item))
+		}
+	}
+
+	return schema
+}
+
+// Merge returns a schema allowing everything both schemas allow: types and
+// properties are combined, only properties required by both stay required
+func Merge(a *Schema, b *Schema) *Schema {
+	if a == nil {
+		return b
+	}
+
+	if b == nil {
+		return a
+	}
+
+	merged := &Schema{Items: Merge(a.Items, b.Items)}
+
+	merged.Type = append(merged.Type, a.Type...)
+	for _, typ := range b.Type {
+		if !merged.Type.contains(typ) {
+			merged.Type = append(merged.Type, typ)
+		}
+	}
+
+	sort.Strings(merged.Type)
+
+	if a.Properties != nil || b.Properties != nil {
+		merged.Properties = map[string]*Schema{}
+
+		for key, property := range a.Properties {
+			merged.Properties[key] = Merge(property, b.Properties[key])
+		}
+
+		for key, property := range b.Properties {
+			if _, ok := a.Properties[key]; !ok {
+				merged.Properties[key] = property
+			}
+		}
+	}
+
+	merged.Required = intersect(a.Required, a.Type, b.Required, b.Type)
+
+	return merged
+}
+
+// intersect returns the properties required by both schemas, a schema which
+// is not an object does not restrict properties
+func intersect(a []string, aTypes Types, b []string, bTypes Types) []string {
+	if !aTypes.contains(TypeObject) {
+		return b
+	}
+
+	if !bTypes.contains(TypeObject) {
+		return a
+	}
+
+	var required []string
+
+	for _, key := range a {
+		for _, other := range b {
+			if key == other {
+				required = append(required, key)
+
+				break
+			}
+		}
+	}
+
+	return required
+}
@@ -0,0 +1,133 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/jsonpath"
+	"github.com/corbado/cli/pkg/schema"
+)
+
+func parse(t *testing.T, body string) any {
+	data, err := jsonpath.Parse(body)
+	assert.NoError(t, err)
+
+	return data
+}
+
+func TestInfer(t *testing.T) {
+	s := schema.Infer(parse(t, `{"action":"authMethods","data":{"username":"jane","age":42,"tags":["a",1],"deleted":null}}`))
+
+	encoded, err := json.Marshal(s)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"action": {"type": "string"},
+			"data": {
+				"type": "object",
+				"properties": {
+					"username": {"type": "string"},
+					"age": {"type": "number"},
+					"tags": {"type": "array", "items": {"type": ["number", "string"]}},
+					"deleted": {"type": "null"}
+				},
+				"required": ["age", "deleted", "tags", "username"]
+			}
+		},
+		"required": ["action", "data"]
+	}`, string(encoded))
+}
+
+func TestMerge(t *testing.T) {
+	merged := schema.Merge(schema.Infer(parse(t, `{"a":1,"b":"x"}`)), schema.Infer(parse(t, `{"a":"1","c":true}`)))
+
+	assert.Equal(t, schema.Types{"number", "string"}, merged.Properties["a"].Type)
+	assert.Equal(t, []string{"a"}, merged.Required)
+	assert.Len(t, merged.Properties, 3)
+}
+
+func TestCompare(t *testing.T) {
+	baseline := schema.Infer(parse(t, `{"data":{"username":"jane","age":42,"users":[{"name":"a"}]}}`))
+	observed := schema.Infer(parse(t, `{"data":{"username":"jane","age":"42","users":[{"name":"a","id":1}],"email":"jane@example.com"}}`))
+
+	drifts := schema.Compare(baseline, observed)
+
+	descriptions := make([]string, 0, len(drifts))
+	for _, drift := range drifts {
+		descriptions = append(descriptions, drift.String())
+	}
+
+	assert.Equal(t, []string{"~ data.age: number -> string", "+ data.email: string", "+ data.users.*.id: number"}, descriptions)
+
+	drifts = schema.Compare(observed, baseline)
+	assert.Contains(t, drifts, schema.Drift{Kind: schema.DriftRemoved, Path: "data.email", Old: schema.Types{"string"}})
+}
+
+func TestBaseline(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schemas.json")
+
+	baseline, err := schema.LoadBaseline(file)
+	assert.NoError(t, err)
+
+	drifts, err := baseline.Check("/webhook authMethods", parse(t, `{"data":{"username":"jane"}}`), true)
+	assert.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	// Not accepted, so it drifts again after loading the baseline
+	drifts, err = baseline.Check("/webhook authMethods", parse(t, `{"data":{"username":"jane","age":1}}`), false)
+	assert.NoError(t, err)
+	assert.Len(t, drifts, 1)
+
+	baseline, err = schema.LoadBaseline(file)
+	assert.NoError(t, err)
+
+	drifts, err = baseline.Check("/webhook authMethods", parse(t, `{"data":{"username":"jane","age":1}}`), true)
+	assert.NoError(t, err)
+	assert.Len(t, drifts, 1)
+
+	// Accepted, the field is optional now
+	drifts, err = baseline.Check("/webhook authMethods", parse(t, `{"data":{"username":"jane"}}`), true)
+	assert.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	baseline, err = schema.LoadBaseline(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"username"}, baseline.Schema("/webhook authMethods").Properties["data"].Required)
+}
+
+func TestBaselineRemovedProperties(t *testing.T) {
+	baseline, err := schema.LoadBaseline(filepath.Join(t.TempDir(), "schemas.json"))
+	assert.NoError(t, err)
+
+	check := func(body string) []schema.Drift {
+		drifts, err := baseline.Check("/webhook authMethods", parse(t, body), false)
+		assert.NoError(t, err)
+
+		return drifts
+	}
+
+	// A single payload does not make its properties required
+	assert.Empty(t, check(`{"data":{"username":"jane","email":"jane@example.com"}}`))
+	assert.Empty(t, check(`{"data":{"username":"john"}}`))
+
+	for i := 0; i < 3; i++ {
+		assert.Empty(t, check(`{"data":{"username":"john"}}`))
+	}
+
+	// Present in every payload seen so far
+	assert.Equal(t, []schema.Drift{{Kind: schema.DriftRemoved, Path: "data.username", Old: schema.Types{"string"}}}, check(`{"data":{}}`))
+	assert.Empty(t, check(`{"data":{"username":"jane"}}`))
+}
+
+func TestLoadBaselineWithInvalidFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schemas.json")
+	assert.NoError(t, os.WriteFile(file, []byte("{"), 0o600))
+
+	_, err := schema.LoadBaseline(file)
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Violation is a value not matching its schema at a dot separated path
+type Violation struct {
+	Path    string
+	Message string
+}
+
+// String returns a single line description (e.g. "data.status: is required")
+func (v Violation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "(root)"
+	}
+
+	return path + ": " + v.Message
+}
+
+// Validate returns all violations of given decoded JSON value against given
+// schema (types, enums, required properties and items)
+func Validate(schema *Schema, value any) []Violation {
+	var violations []Violation
+
+	validate(nil, schema, value, &violations)
+
+	return violations
+}
+
+func validate(segments []string, schema *Schema, value any, violations *[]Violation) {
+	path := strings.Join(segments, ".")
+
+	if len(schema.Type) > 0 && !matchesType(schema.Type, value) {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf("must be %s (got %s)", strings.Join(schema.Type, " or "), TypeOf(value))})
+
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf("must be one of %s (got %s)", encodeAll(schema.Enum), encode(value))})
+
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range schema.Required {
+			if _, ok := v[key]; !ok {
+				*violations = append(*violations, Violation{Path: strings.Join(appendSegment(segments, key), "."), Message: "is required"})
+			}
+		}
+
+		keys := make([]string, 0, len(schema.Properties))
+		for key := range schema.Properties {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if property, ok := v[key]; ok {
+				validate(appendSegment(segments, key), schema.Properties[key], property, violations)
+			}
+		}
+
+	case []any:
+		if schema.Items == nil {
+			return
+		}
+
+		for i, item := range v {
+			validate(appendSegment(segments, strconv.Itoa(i)), schema.Items, item, violations)
+		}
+	}
+}
+
+func matchesType(types Types, value any) bool {
+	typ := TypeOf(value)
+	if types.contains(typ) {
+		return true
+	}
+
+	if typ == TypeNumber && types.contains(TypeInteger) {
+		number, ok := value.(json.Number)
+
+		return ok && !strings.ContainsAny(number.String(), ".eE")
+	}
+
+	return false
+}
+
+func inEnum(enum []any, value any) bool {
+	encoded := encode(value)
+
+	for _, allowed := range enum {
+		if encode(allowed) == encoded {
+			return true
+		}
+	}
+
+	return false
+}
+
+func encode(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(encoded)
+}
+
+func encodeAll(values []any) string {
+	encoded := make([]string, 0, len(values))
+	for _, value := range values {
+		encoded = append(encoded, encode(value))
+	}
+
+	return strings.Join(encoded, ", ")
+}
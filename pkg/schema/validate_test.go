@@ -0,0 +1,60 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/schema"
+)
+
+func TestValidate(t *testing.T) {
+	s := &schema.Schema{
+		Type: schema.Types{"object"},
+		Properties: map[string]*schema.Schema{
+			"status": {Type: schema.Types{"string"}, Enum: []any{"exists", "not_exists"}},
+			"count":  {Type: schema.Types{"integer"}},
+			"tags":   {Type: schema.Types{"array"}, Items: &schema.Schema{Type: schema.Types{"string"}}},
+		},
+		Required: []string{"status", "success"},
+	}
+
+	violations := schema.Validate(s, parse(t, `{"status":"yes","count":1.5,"tags":["a",2]}`))
+
+	descriptions := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		descriptions = append(descriptions, violation.String())
+	}
+
+	assert.Equal(t, []string{
+		"success: is required",
+		"count: must be integer (got number)",
+		`status: must be one of "exists", "not_exists" (got "yes")`,
+		"tags.1: must be string (got number)",
+	}, descriptions)
+
+	assert.Empty(t, schema.Validate(s, parse(t, `{"status":"exists","success":true,"count":2}`)))
+	assert.Equal(t, "(root): must be object (got array)", schema.Validate(s, parse(t, `[]`))[0].String())
+}
+
+func TestActions(t *testing.T) {
+	actions, err := schema.Actions()
+	assert.NoError(t, err)
+
+	names := make([]string, 0, len(actions))
+	for _, action := range actions {
+		names = append(names, action.Name)
+		assert.NotNil(t, action.Request, action.Name)
+		assert.NotNil(t, action.Response, action.Name)
+	}
+
+	assert.Equal(t, []string{"authMethods", "passwordVerify"}, names)
+
+	action, ok := schema.LookupAction("authMethods")
+	assert.True(t, ok)
+	assert.Empty(t, schema.Validate(action.Response, parse(t, `{"responseID":"who-1","data":{"status":"exists"}}`)))
+	assert.Empty(t, schema.Validate(action.Request, parse(t, `{"id":"who-1","projectID":"pro-1","action":"authMethods","data":{"username":"jane"}}`)))
+
+	_, ok = schema.LookupAction("unknown")
+	assert.False(t, ok)
+}
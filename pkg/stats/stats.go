@@ -0,0 +1,170 @@
+package stats
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// reservoirSize is the maximum number of latencies kept for percentiles, a
+// random sample is kept once more exchanges were recorded
+const reservoirSize = 1024
+
+type Stats struct {
+	started time.Time
+	random  *rand.Rand
+
+	lock          sync.Mutex
+	total         int
+	timeouts      int
+	errors        int
+	statusClasses map[string]int
+	paths         map[string]int
+	latencies     []time.Duration
+	minLatency    time.Duration
+	latencySum    time.Duration
+	bytesIn       int
+	bytesOut      int
+}
+
+type Summary struct {
+	Started       time.Time
+	Duration      time.Duration
+	Total         int
+	Timeouts      int
+	Errors        int
+	StatusClasses map[string]int
+	Paths         map[string]int
+	MinLatency    time.Duration
+	AvgLatency    time.Duration
+	P95Latency    time.Duration
+	BytesIn       int
+	BytesOut      int
+}
+
+// New returns new stats instance, the session starts now
+func New() *Stats {
+	return &Stats{
+		started:       time.Now(),
+		random:        rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec
+		statusClasses: make(map[string]int),
+		paths:         make(map[string]int),
+	}
+}
+
+// Observe records given exchange (implements tunnel.Observer)
+func (s *Stats) Observe(exchange *tunnel.Exchange) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.total++
+	s.paths[exchange.Request.Path]++
+	s.bytesIn += len(exchange.Request.Body)
+	s.observeLatency(exchange.Latency)
+
+	if exchange.TimedOut {
+		s.timeouts++
+	}
+
+	if exchange.Error != nil {
+		s.errors++
+	}
+
+	if exchange.Response != nil {
+		s.statusClasses[StatusClass(exchange.Response.Status)]++
+		s.bytesOut += len(exchange.Response.Body)
+	}
+}
+
+// Summary returns a snapshot of all recorded exchanges
+func (s *Stats) Summary() *Summary {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	summary := &Summary{
+		Started:       s.started,
+		Duration:      time.Since(s.started),
+		Total:         s.total,
+		Timeouts:      s.timeouts,
+		Errors:        s.errors,
+		StatusClasses: make(map[string]int, len(s.statusClasses)),
+		Paths:         make(map[string]int, len(s.paths)),
+		BytesIn:       s.bytesIn,
+		BytesOut:      s.bytesOut,
+	}
+
+	for class, count := range s.statusClasses {
+		summary.StatusClasses[class] = count
+	}
+
+	for path, count := range s.paths {
+		summary.Paths[path] = count
+	}
+
+	if len(s.latencies) > 0 {
+		latencies := make([]time.Duration, len(s.latencies))
+		copy(latencies, s.latencies)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		summary.MinLatency = s.minLatency
+		summary.AvgLatency = s.latencySum / time.Duration(s.total)
+		summary.P95Latency = Percentile(latencies, 95)
+	}
+
+	return summary
+}
+
+// observeLatency records given latency, minimum and average are exact while
+// the percentiles are based on a bounded reservoir sample (algorithm R)
+func (s *Stats) observeLatency(latency time.Duration) {
+	if s.total == 1 || latency < s.minLatency {
+		s.minLatency = latency
+	}
+
+	s.latencySum += latency
+
+	if len(s.latencies) < reservoirSize {
+		s.latencies = append(s.latencies, latency)
+
+		return
+	}
+
+	if i := s.random.Intn(s.total); i < reservoirSize {
+		s.latencies[i] = latency
+	}
+}
+
+// StatusClass returns the class (e.g. 2xx) of given HTTP status code
+func StatusClass(status int) string {
+	return fmt.Sprintf("%dxx", status/100)
+}
+
+// Percentile returns the p-th percentile (nearest-rank) of given sorted latencies
+func Percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// SortedKeys returns the keys of given map sorted alphabetically
+func SortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
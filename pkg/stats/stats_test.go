@@ -0,0 +1,82 @@
+package stats_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/stats"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func exchange(path string, status int, latency time.Duration) *tunnel.Exchange {
+	return &tunnel.Exchange{
+		Time:     time.Now(),
+		Request:  &tunnel.WebhookRequest{ID: "who-1", Path: path, Body: "1234"},
+		Response: &tunnel.WebhookResponse{ID: "who-1", Status: status, Body: "12"},
+		Latency:  latency,
+	}
+}
+
+func TestEmptySummary(t *testing.T) {
+	summary := stats.New().Summary()
+
+	assert.Equal(t, 0, summary.Total)
+	assert.Equal(t, time.Duration(0), summary.MinLatency)
+	assert.Empty(t, summary.Paths)
+}
+
+func TestSummary(t *testing.T) {
+	s := stats.New()
+	s.Observe(exchange("/a", 200, 10*time.Millisecond))
+	s.Observe(exchange("/a", 204, 30*time.Millisecond))
+	s.Observe(exchange("/b", 500, 20*time.Millisecond))
+
+	timeout := exchange("/b", 504, 10*time.Second)
+	timeout.TimedOut = true
+	s.Observe(timeout)
+
+	failed := exchange("/b", 500, time.Millisecond)
+	failed.Error = errors.New("connection refused")
+	s.Observe(failed)
+
+	summary := s.Summary()
+	assert.Equal(t, 5, summary.Total)
+	assert.Equal(t, 1, summary.Timeouts)
+	assert.Equal(t, 1, summary.Errors)
+	assert.Equal(t, map[string]int{"2xx": 2, "5xx": 3}, summary.StatusClasses)
+	assert.Equal(t, map[string]int{"/a": 2, "/b": 3}, summary.Paths)
+	assert.Equal(t, time.Millisecond, summary.MinLatency)
+	assert.Equal(t, 2012200*time.Microsecond, summary.AvgLatency)
+	assert.Equal(t, 10*time.Second, summary.P95Latency)
+	assert.Equal(t, 20, summary.BytesIn)
+	assert.Equal(t, 10, summary.BytesOut)
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	assert.Equal(t, 95*time.Millisecond, stats.Percentile(latencies, 95))
+	assert.Equal(t, 100*time.Millisecond, stats.Percentile(latencies, 100))
+	assert.Equal(t, time.Millisecond, stats.Percentile(latencies[:1], 95))
+}
+
+func TestSummaryBoundedLatencies(t *testing.T) {
+	s := stats.New()
+	for i := 1; i <= 10000; i++ {
+		s.Observe(exchange("/a", 200, time.Duration(i)*time.Millisecond))
+	}
+
+	summary := s.Summary()
+	assert.Equal(t, 10000, summary.Total)
+	assert.Equal(t, time.Millisecond, summary.MinLatency)
+	assert.Equal(t, 5000500*time.Microsecond, summary.AvgLatency)
+
+	// Percentile is based on a sample, so only roughly 9500ms
+	assert.InDelta(t, float64(9500*time.Millisecond), float64(summary.P95Latency), float64(500*time.Millisecond))
+}
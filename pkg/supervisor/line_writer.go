@@ -0,0 +1,67 @@
+package supervisor
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// LineWriter writes complete lines to the underlying writer, each prefixed
+// with the given prefix. It is safe for concurrent use.
+type LineWriter struct {
+	out    io.Writer
+	prefix string
+
+	lock   sync.Mutex
+	buffer []byte
+}
+
+// NewLineWriter returns new line writer instance
+func NewLineWriter(out io.Writer, prefix string) *LineWriter {
+	return &LineWriter{
+		out:    out,
+		prefix: prefix,
+	}
+}
+
+// Write buffers given bytes and writes all complete lines
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.buffer = append(w.buffer, p...)
+
+	for {
+		i := bytes.IndexByte(w.buffer, '\n')
+		if i < 0 {
+			break
+		}
+
+		if err := w.writeLine(w.buffer[:i+1]); err != nil {
+			return 0, err
+		}
+
+		w.buffer = w.buffer[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the remaining incomplete line
+func (w *LineWriter) Flush() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if len(w.buffer) == 0 {
+		return
+	}
+
+	_ = w.writeLine(append(w.buffer, '\n'))
+	w.buffer = nil
+}
+
+func (w *LineWriter) writeLine(line []byte) error {
+	_, err := w.out.Write(append([]byte(w.prefix), line...))
+
+	return err
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package supervisor
+
+import (
+	"os/exec"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+func shellCommand(command string) *exec.Cmd {
+	return exec.Command("cmd", "/C", command)
+}
+
+// terminate kills the whole process tree since Windows has no process groups
+// which can be signaled gracefully
+func terminate(cmd *exec.Cmd) error {
+	return kill(cmd)
+}
+
+func kill(cmd *exec.Cmd) error {
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+		if err := cmd.Process.Kill(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
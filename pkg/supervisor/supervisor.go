@@ -0,0 +1,199 @@
+package supervisor
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	minRestartDelay = time.Second
+	maxRestartDelay = 30 * time.Second
+	stableRuntime   = 10 * time.Second
+	stopTimeout     = 5 * time.Second
+)
+
+type process struct {
+	cmd     *exec.Cmd
+	started time.Time
+	done    chan struct{}
+}
+
+type Supervisor struct {
+	command string
+	out     *LineWriter
+
+	lock         sync.Mutex
+	process      *process
+	stopped      bool
+	restartDelay time.Duration
+	restartTimer *time.Timer
+}
+
+// New returns new supervisor instance for given shell command, the output of
+// the command is written line by line to given writer prefixed with given prefix
+func New(command string, out io.Writer, prefix string) *Supervisor {
+	return &Supervisor{
+		command:      command,
+		out:          NewLineWriter(out, prefix),
+		restartDelay: minRestartDelay,
+	}
+}
+
+// Start starts the command, it gets restarted automatically if it exits
+func (s *Supervisor) Start() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.start()
+}
+
+// Restart stops the running command and starts it again
+func (s *Supervisor) Restart() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stopped {
+		return nil
+	}
+
+	s.printf("restarting ...")
+
+	if err := s.stop(); err != nil {
+		return err
+	}
+
+	return s.start()
+}
+
+// Stop stops the command, it is not restarted anymore
+func (s *Supervisor) Stop() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stopped = true
+
+	return s.stop()
+}
+
+func (s *Supervisor) start() error {
+	if s.restartTimer != nil {
+		s.restartTimer.Stop()
+		s.restartTimer = nil
+	}
+
+	cmd := shellCommand(s.command)
+	cmd.Stdout = s.out
+	cmd.Stderr = s.out
+
+	if err := cmd.Start(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	p := &process{
+		cmd:     cmd,
+		started: time.Now(),
+		done:    make(chan struct{}),
+	}
+	s.process = p
+
+	s.printf("started '%s' (pid %d)", s.command, cmd.Process.Pid)
+
+	go s.wait(p)
+
+	return nil
+}
+
+func (s *Supervisor) wait(p *process) {
+	err := p.cmd.Wait()
+	s.out.Flush()
+	close(p.done)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Process was stopped on purpose (restart or stop)
+	if s.process != p {
+		return
+	}
+
+	s.process = nil
+
+	if time.Since(p.started) >= stableRuntime {
+		s.restartDelay = minRestartDelay
+	}
+
+	s.printf("exited (%s), restarting in %s ...", exitReason(err), s.restartDelay)
+
+	s.restartTimer = time.AfterFunc(s.restartDelay, func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if s.stopped || s.process != nil {
+			return
+		}
+
+		if err := s.start(); err != nil {
+			s.printf("failed to restart: %s", err.Error())
+		}
+	})
+
+	s.restartDelay *= 2
+	if s.restartDelay > maxRestartDelay {
+		s.restartDelay = maxRestartDelay
+	}
+}
+
+func (s *Supervisor) stop() error {
+	if s.restartTimer != nil {
+		s.restartTimer.Stop()
+		s.restartTimer = nil
+	}
+
+	p := s.process
+	if p == nil {
+		return nil
+	}
+
+	s.process = nil
+
+	if err := terminate(p.cmd); err != nil {
+		return err
+	}
+
+	select {
+	case <-p.done:
+		return nil
+
+	case <-time.After(stopTimeout):
+	}
+
+	if err := kill(p.cmd); err != nil {
+		return err
+	}
+
+	<-p.done
+
+	return nil
+}
+
+func (s *Supervisor) printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(s.out, format+"\n", a...)
+}
+
+func exitReason(err error) string {
+	if err == nil {
+		return "exit code 0"
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Sprintf("exit code %d", exitErr.ExitCode())
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,121 @@
+//go:build !windows
+
+package supervisor_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/supervisor"
+)
+
+type syncBuffer struct {
+	lock   sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.buffer.String()
+}
+
+func TestLineWriter(t *testing.T) {
+	out := new(bytes.Buffer)
+	w := supervisor.NewLineWriter(out, "[app] ")
+
+	_, err := w.Write([]byte("first\nsec"))
+	assert.NoError(t, err)
+	assert.Equal(t, "[app] first\n", out.String())
+
+	_, err = w.Write([]byte("ond\nthird"))
+	assert.NoError(t, err)
+	w.Flush()
+	assert.Equal(t, "[app] first\n[app] second\n[app] third\n", out.String())
+}
+
+func TestSupervisorStartAndStop(t *testing.T) {
+	out := &syncBuffer{}
+	s := supervisor.New("echo hello && sleep 60", out, "[app] ")
+
+	assert.NoError(t, s.Start())
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "[app] hello\n")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, s.Stop())
+	assert.NotContains(t, out.String(), "restarting")
+}
+
+func TestSupervisorRestartsAfterExit(t *testing.T) {
+	out := &syncBuffer{}
+	s := supervisor.New("echo run", out, "[app] ")
+
+	assert.NoError(t, s.Start())
+	assert.Eventually(t, func() bool {
+		return strings.Count(out.String(), "[app] run\n") >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, out.String(), "exited (exit code 0), restarting in 1s")
+
+	assert.NoError(t, s.Stop())
+}
+
+func TestSupervisorRestart(t *testing.T) {
+	out := &syncBuffer{}
+	s := supervisor.New("echo run && sleep 60", out, "[app] ")
+
+	assert.NoError(t, s.Start())
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "[app] run\n")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, s.Restart())
+	assert.Eventually(t, func() bool {
+		return strings.Count(out.String(), "[app] run\n") == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NotContains(t, out.String(), "exited")
+
+	assert.NoError(t, s.Stop())
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0600))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0700))
+
+	changes := make(chan struct{}, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go supervisor.Watch(ctx, []string{dir}, 10*time.Millisecond, func() {
+		changes <- struct{}{}
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0600))
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, changes)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "server.go"), []byte("package main"), 0600))
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("change not detected")
+	}
+}
@@ -0,0 +1,92 @@
+package supervisor
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch polls given paths (files or directories, walked recursively) every
+// interval and calls onChange if a file was added, removed or modified until
+// given context is done. Hidden directories, node_modules and vendor are skipped.
+func Watch(ctx context.Context, paths []string, interval time.Duration, onChange func()) {
+	previous := snapshot(paths)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			current := snapshot(paths)
+			if changed(previous, current) {
+				onChange()
+			}
+
+			previous = current
+		}
+	}
+}
+
+func snapshot(paths []string) map[string]fileState {
+	result := make(map[string]fileState)
+
+	for _, root := range paths {
+		_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+
+			if entry.IsDir() {
+				if path != root && skipDir(entry.Name()) {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+
+			result[path] = fileState{
+				modTime: info.ModTime(),
+				size:    info.Size(),
+			}
+
+			return nil
+		})
+	}
+
+	return result
+}
+
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor"
+}
+
+func changed(previous map[string]fileState, current map[string]fileState) bool {
+	if len(previous) != len(current) {
+		return true
+	}
+
+	for path, state := range current {
+		previousState, ok := previous[path]
+		if !ok || previousState.size != state.size || !previousState.modTime.Equal(state.modTime) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package testsuite
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes given results as JUnit XML report (understood by most CI systems)
+func WriteJUnit(w io.Writer, suite *Suite, results []*Result) error {
+	name := suite.Name
+	if name == "" {
+		name = "webhooks"
+	}
+
+	junitSuite := junitTestSuite{
+		Name:  name,
+		Tests: len(results),
+	}
+
+	total := time.Duration(0)
+	for _, result := range results {
+		total += result.Duration
+
+		testCase := junitTestCase{
+			Name:      result.Test.Name,
+			ClassName: name,
+			Time:      seconds(result.Duration),
+		}
+
+		switch {
+		case result.Error != nil:
+			junitSuite.Errors++
+			testCase.Error = &junitMessage{Message: result.Error.Error(), Text: result.Error.Error()}
+
+		case len(result.Failures) > 0:
+			junitSuite.Failures++
+			testCase.Failure = &junitMessage{Message: result.Failures[0], Text: strings.Join(result.Failures, "\n")}
+		}
+
+		junitSuite.Cases = append(junitSuite.Cases, testCase)
+	}
+
+	junitSuite.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.WithStack(err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(&junitTestSuites{Suites: []junitTestSuite{junitSuite}}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,149 @@
+package testsuite
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/corbado/cli/pkg/jsonpath"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// Forwarder forwards webhook requests to the local address (implemented by tunnel.Tunnel)
+type Forwarder interface {
+	Forward(req *tunnel.WebhookRequest) (*tunnel.Exchange, error)
+}
+
+// Result is the outcome of a single test
+type Result struct {
+	Test     *Test
+	Exchange *tunnel.Exchange
+	Duration time.Duration
+
+	// Failures lists all assertions that did not hold
+	Failures []string
+
+	// Error is set if the test could not be run at all
+	Error error
+}
+
+// Passed returns true if the test ran and all assertions held
+func (r *Result) Passed() bool {
+	return r.Error == nil && len(r.Failures) == 0
+}
+
+// Run runs all tests of given suite one after another, onResult gets called
+// after each test (e.g. for progress output)
+func Run(suite *Suite, forwarder Forwarder, onResult func(result *Result)) []*Result {
+	results := make([]*Result, 0, len(suite.Tests))
+
+	for i, test := range suite.Tests {
+		result := &Result{Test: test}
+		start := time.Now()
+
+		req, err := test.WebhookRequest(i)
+		if err == nil {
+			result.Exchange, err = forwarder.Forward(req)
+		}
+
+		result.Duration = time.Since(start)
+
+		if err != nil {
+			result.Error = err
+		} else {
+			result.Failures = Check(&test.Expect, result.Exchange)
+		}
+
+		if onResult != nil {
+			onResult(result)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// Check returns all assertions of given expectation not holding for given exchange
+func Check(expect *Expect, exchange *tunnel.Exchange) []string {
+	var failures []string
+
+	if exchange.Error != nil {
+		failures = append(failures, fmt.Sprintf("local address failed: %s", exchange.Error.Error()))
+	}
+
+	if exchange.TimedOut {
+		failures = append(failures, "local address timed out")
+	}
+
+	resp := exchange.Response
+
+	if expect.Status != 0 && resp.Status != expect.Status {
+		failures = append(failures, fmt.Sprintf("expected status %d, got %d", expect.Status, resp.Status))
+	}
+
+	for _, name := range sortedKeys(expect.Headers) {
+		actual := header(resp.Headers, name)
+		if actual != expect.Headers[name] {
+			failures = append(failures, fmt.Sprintf("expected header %s to be %q, got %q", name, expect.Headers[name], actual))
+		}
+	}
+
+	if expect.BodyContains != "" && !strings.Contains(resp.Body, expect.BodyContains) {
+		failures = append(failures, fmt.Sprintf("expected body to contain %q", expect.BodyContains))
+	}
+
+	if len(expect.JSON) > 0 {
+		failures = append(failures, checkJSON(expect.JSON, resp.Body)...)
+	}
+
+	return failures
+}
+
+func checkJSON(expected map[string]any, body string) []string {
+	data, err := jsonpath.Parse(body)
+	if err != nil {
+		return []string{"expected JSON body, got invalid JSON"}
+	}
+
+	var failures []string
+	for _, path := range sortedKeys(expected) {
+		want := jsonpath.String(expected[path])
+
+		value, ok := jsonpath.Lookup(data, path)
+		if !ok {
+			failures = append(failures, fmt.Sprintf("expected JSON path %s to be %s, but it is missing", path, want))
+
+			continue
+		}
+
+		if got := jsonpath.String(value); got != want {
+			failures = append(failures, fmt.Sprintf("expected JSON path %s to be %s, got %s", path, want, got))
+		}
+	}
+
+	return failures
+}
+
+func header(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(name) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,154 @@
+package testsuite
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/corbado/cli/pkg/tunnel"
+	"github.com/corbado/cli/pkg/webhook"
+)
+
+// Suite is a list of webhook requests together with the expected responses
+type Suite struct {
+	Name  string  `yaml:"name"`
+	Tests []*Test `yaml:"tests"`
+}
+
+// Test is a single webhook request and its expected response
+type Test struct {
+	Name    string  `yaml:"name"`
+	Request Request `yaml:"request"`
+	Expect  Expect  `yaml:"expect"`
+}
+
+// Request is the webhook request sent to the local address, the body is
+// either a string or a YAML structure which gets encoded as JSON
+type Request struct {
+	ID      string            `yaml:"id"`
+	Path    string            `yaml:"path"`
+	Headers map[string]string `yaml:"headers"`
+	Body    yaml.Node         `yaml:"body"`
+
+	// Validate checks the data of Corbado webhook requests against their typed
+	// request (true if not given), disable it for tests of invalid data
+	Validate *bool `yaml:"validate"`
+}
+
+// Expect defines the assertions on the response, all given ones must hold
+type Expect struct {
+	// Status is the expected HTTP status (not checked if 0)
+	Status int `yaml:"status"`
+
+	// Headers are the expected response headers (names are case-insensitive)
+	Headers map[string]string `yaml:"headers"`
+
+	// JSON maps JSON paths of the response body (e.g. data.status or
+	// data.users.0.name) to the expected values
+	JSON map[string]any `yaml:"json"`
+
+	// BodyContains is a string the response body must contain
+	BodyContains string `yaml:"bodyContains"`
+}
+
+// Load loads the suite from given YAML file
+func Load(file string) (*Suite, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return Parse(data)
+}
+
+// Parse parses and validates given YAML suite
+func Parse(data []byte) (*Suite, error) {
+	suite := &Suite{}
+	if err := yaml.Unmarshal(data, suite); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if len(suite.Tests) == 0 {
+		return nil, errors.New("suite contains no tests")
+	}
+
+	for i, test := range suite.Tests {
+		if test.Name == "" {
+			test.Name = fmt.Sprintf("test %d", i+1)
+		}
+
+		if test.Request.Path == "" {
+			return nil, errors.Errorf("%s: request path is missing", test.Name)
+		}
+
+		body, err := test.Request.body()
+		if err != nil {
+			return nil, errors.Errorf("%s: invalid request body: %s", test.Name, err.Error())
+		}
+
+		validate := test.Request.Validate == nil || *test.Request.Validate
+		if validate {
+			if err := validateBody(body); err != nil {
+				return nil, errors.Errorf("%s: invalid request body: %s (set validate: false to send it anyway)", test.Name, err.Error())
+			}
+		}
+	}
+
+	return suite, nil
+}
+
+// WebhookRequest returns the webhook request of given test
+func (t *Test) WebhookRequest(index int) (*tunnel.WebhookRequest, error) {
+	body, err := t.Request.body()
+	if err != nil {
+		return nil, err
+	}
+
+	id := t.Request.ID
+	if id == "" {
+		id = fmt.Sprintf("test-%d", index+1)
+	}
+
+	return &tunnel.WebhookRequest{
+		ID:      id,
+		Path:    t.Request.Path,
+		Headers: t.Request.Headers,
+		Body:    body,
+	}, nil
+}
+
+// validateBody checks the data of Corbado webhook requests against their
+// typed request, other bodies (e.g. plain text or without action) are allowed
+func validateBody(body string) error {
+	req, err := webhook.Parse([]byte(body))
+	if err != nil {
+		return nil
+	}
+
+	return req.Validate()
+}
+
+func (r *Request) body() (string, error) {
+	switch r.Body.Kind {
+	case 0:
+		return "", nil
+
+	case yaml.ScalarNode:
+		return r.Body.Value, nil
+	}
+
+	var data any
+	if err := r.Body.Decode(&data); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return string(body), nil
+}
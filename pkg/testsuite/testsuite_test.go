@@ -0,0 +1,133 @@
+package testsuite_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/testsuite"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+const suiteYAML = `
+name: Webhooks
+tests:
+  - name: existing user
+    request:
+      path: /webhook
+      headers:
+        Content-Type: application/json
+      body:
+        id: who-1
+        action: authMethods
+        data:
+          username: jane@example.com
+    expect:
+      status: 200
+      headers:
+        content-type: application/json
+      json:
+        responseID: who-1
+        data.status: exists
+  - request:
+      path: /raw
+      body: "plain text"
+    expect:
+      bodyContains: ok
+`
+
+type forwarderFunc func(req *tunnel.WebhookRequest) (*tunnel.Exchange, error)
+
+func (f forwarderFunc) Forward(req *tunnel.WebhookRequest) (*tunnel.Exchange, error) {
+	return f(req)
+}
+
+func TestParse(t *testing.T) {
+	suite, err := testsuite.Parse([]byte(suiteYAML))
+	assert.NoError(t, err)
+	assert.Equal(t, "Webhooks", suite.Name)
+	assert.Len(t, suite.Tests, 2)
+	assert.Equal(t, "test 2", suite.Tests[1].Name)
+
+	req, err := suite.Tests[0].WebhookRequest(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-1", req.ID)
+	assert.Equal(t, `{"action":"authMethods","data":{"username":"jane@example.com"},"id":"who-1"}`, req.Body)
+
+	req, err = suite.Tests[1].WebhookRequest(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "plain text", req.Body)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := testsuite.Parse([]byte("name: empty"))
+	assert.EqualError(t, err, "suite contains no tests")
+
+	_, err = testsuite.Parse([]byte("tests:\n  - name: no path\n"))
+	assert.EqualError(t, err, "no path: request path is missing")
+
+	_, err = testsuite.Parse([]byte("tests:\n  - name: wrong data\n    request:\n      path: /webhook\n      body:\n        action: authMethods\n        data:\n          username: 42\n"))
+	assert.ErrorContains(t, err, "wrong data: invalid request body: invalid data of authMethods webhook request")
+}
+
+func TestParseWithoutValidation(t *testing.T) {
+	suite, err := testsuite.Parse([]byte("tests:\n  - name: malformed\n    request:\n      path: /webhook\n      validate: false\n      body:\n        action: passwordVerify\n        data:\n          password: 42\n    expect:\n      status: 400\n"))
+	assert.NoError(t, err)
+
+	req, err := suite.Tests[0].WebhookRequest(0)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"action":"passwordVerify","data":{"password":42}}`, req.Body)
+}
+
+func TestRun(t *testing.T) {
+	suite, err := testsuite.Parse([]byte(suiteYAML))
+	assert.NoError(t, err)
+
+	forwarder := forwarderFunc(func(req *tunnel.WebhookRequest) (*tunnel.Exchange, error) {
+		if req.Path == "/raw" {
+			return nil, errors.New("boom")
+		}
+
+		return &tunnel.Exchange{
+			Request: req,
+			Response: &tunnel.WebhookResponse{
+				ID:      req.ID,
+				Status:  http.StatusOK,
+				Headers: map[string]string{"Content-Type": "text/plain"},
+				Body:    `{"responseID":"who-1","data":{"status":"not_exists"}}`,
+			},
+		}, nil
+	})
+
+	called := 0
+	results := testsuite.Run(suite, forwarder, func(result *testsuite.Result) {
+		called++
+	})
+
+	assert.Equal(t, 2, called)
+	assert.False(t, results[0].Passed())
+	assert.Equal(t, []string{
+		`expected header content-type to be "application/json", got "text/plain"`,
+		"expected JSON path data.status to be exists, got not_exists",
+	}, results[0].Failures)
+	assert.EqualError(t, results[1].Error, "boom")
+
+	report := new(bytes.Buffer)
+	assert.NoError(t, testsuite.WriteJUnit(report, suite, results))
+	assert.Contains(t, report.String(), `<testsuite name="Webhooks" tests="2" failures="1" errors="1"`)
+	assert.Contains(t, report.String(), `<failure message="expected header content-type to be &#34;application/json&#34;, got &#34;text/plain&#34;">`)
+	assert.Contains(t, report.String(), `<error message="boom">boom</error>`)
+}
+
+func TestCheckLocalError(t *testing.T) {
+	failures := testsuite.Check(&testsuite.Expect{Status: http.StatusOK}, &tunnel.Exchange{
+		Response: &tunnel.WebhookResponse{Status: http.StatusServiceUnavailable},
+		Error:    &tunnel.LocalError{Kind: tunnel.LocalErrorRefused, Message: "connection refused"},
+	})
+
+	assert.Len(t, failures, 2)
+	assert.Equal(t, "expected status 200, got 503", failures[1])
+}
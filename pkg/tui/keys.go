@@ -0,0 +1,97 @@
+package tui
+
+// KeyCode identifies special keys, printable characters are KeyRune
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	KeyCtrlC
+)
+
+// Key is a single key press
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+const escape = 0x1b
+
+// ParseKeys parses given raw terminal input into key presses
+func ParseKeys(input []byte) []Key {
+	var keys []Key
+
+	runes := []rune(string(input))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch r {
+		case escape:
+			// Escape sequences like ESC [ A (arrow up)
+			if i+2 < len(runes) && (runes[i+1] == '[' || runes[i+1] == 'O') {
+				if key, length, ok := parseSequence(runes[i+2:]); ok {
+					keys = append(keys, key)
+					i += 1 + length
+
+					continue
+				}
+			}
+
+			keys = append(keys, Key{Code: KeyEscape})
+
+		case '\r', '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+
+		case 0x7f, 0x08:
+			keys = append(keys, Key{Code: KeyBackspace})
+
+		case 0x03:
+			keys = append(keys, Key{Code: KeyCtrlC})
+
+		default:
+			if r >= ' ' {
+				keys = append(keys, Key{Code: KeyRune, Rune: r})
+			}
+		}
+	}
+
+	return keys
+}
+
+// parseSequence parses the part of an escape sequence after ESC [, it
+// returns the key and the number of runes consumed
+func parseSequence(runes []rune) (Key, int, bool) {
+	switch runes[0] {
+	case 'A':
+		return Key{Code: KeyUp}, 1, true
+
+	case 'B':
+		return Key{Code: KeyDown}, 1, true
+
+	case 'H':
+		return Key{Code: KeyHome}, 1, true
+
+	case 'F':
+		return Key{Code: KeyEnd}, 1, true
+	}
+
+	if len(runes) >= 2 && runes[1] == '~' {
+		switch runes[0] {
+		case '5':
+			return Key{Code: KeyPageUp}, 2, true
+
+		case '6':
+			return Key{Code: KeyPageDown}, 2, true
+		}
+	}
+
+	return Key{}, 0, false
+}
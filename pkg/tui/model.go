@@ -0,0 +1,443 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/format"
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/stats"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// Action is what the caller has to do after a key press
+type Action int
+
+const (
+	ActionNone Action = iota
+	ActionQuit
+	ActionReplay
+	ActionExport
+)
+
+const (
+	maxEntries   = 1000
+	statusErrors = "errors"
+	helpLine     = "↑/↓ select  / path filter  s status filter  c clear filters  r replay  x export  f follow  q quit"
+)
+
+// Entry is a webhook request shown in the table
+type Entry struct {
+	Exchange *tunnel.Exchange
+	Replay   bool
+}
+
+// Model is the state of the dashboard, it is not safe for concurrent use
+type Model struct {
+	redactor *redact.Redactor
+	entries  []*Entry
+
+	// selected and offset are indices into the filtered entries
+	selected int
+	offset   int
+	follow   bool
+
+	statusFilter string
+	pathFilter   string
+
+	// input is the path filter being typed (nil if not typing)
+	input   *string
+	message string
+}
+
+// NewModel returns new model, new entries get selected automatically (follow mode)
+func NewModel(redactor *redact.Redactor) *Model {
+	return &Model{
+		redactor: redactor,
+		follow:   true,
+	}
+}
+
+// Add adds given entry, only the last 1000 entries are kept
+func (m *Model) Add(entry *Entry) {
+	m.entries = append(m.entries, entry)
+	if len(m.entries) > maxEntries {
+		m.entries = m.entries[len(m.entries)-maxEntries:]
+	}
+
+	if m.follow {
+		m.selected = len(m.filtered()) - 1
+	}
+
+	m.clampSelection()
+}
+
+// SetMessage sets the message shown in the status line
+func (m *Model) SetMessage(message string) {
+	m.message = message
+}
+
+// Selected returns the selected entry (nil if none is shown)
+func (m *Model) Selected() *Entry {
+	entries := m.filtered()
+	if m.selected < 0 || m.selected >= len(entries) {
+		return nil
+	}
+
+	return entries[m.selected]
+}
+
+// HandleKey updates the model according to given key press
+func (m *Model) HandleKey(key Key) Action {
+	if m.input != nil {
+		m.handleInputKey(key)
+
+		return ActionNone
+	}
+
+	switch key.Code {
+	case KeyCtrlC:
+		return ActionQuit
+
+	case KeyUp:
+		m.move(-1)
+
+	case KeyDown:
+		m.move(1)
+
+	case KeyPageUp:
+		m.move(-10)
+
+	case KeyPageDown:
+		m.move(10)
+
+	case KeyHome:
+		m.move(-len(m.entries))
+
+	case KeyEnd:
+		m.move(len(m.entries))
+
+	case KeyRune:
+		return m.handleRune(key.Rune)
+	}
+
+	return ActionNone
+}
+
+func (m *Model) handleRune(r rune) Action {
+	switch r {
+	case 'q':
+		return ActionQuit
+
+	case 'k':
+		m.move(-1)
+
+	case 'j':
+		m.move(1)
+
+	case 'f':
+		m.follow = !m.follow
+		if m.follow {
+			m.selected = len(m.filtered()) - 1
+			m.clampSelection()
+		}
+
+	case '/':
+		input := m.pathFilter
+		m.input = &input
+
+	case 's':
+		m.statusFilter = nextStatusFilter(m.statusFilter)
+		m.clampSelection()
+
+	case 'c':
+		m.statusFilter = ""
+		m.pathFilter = ""
+		m.clampSelection()
+
+	case 'r':
+		if m.Selected() != nil {
+			return ActionReplay
+		}
+
+	case 'x':
+		if m.Selected() != nil {
+			return ActionExport
+		}
+	}
+
+	return ActionNone
+}
+
+func (m *Model) handleInputKey(key Key) {
+	switch key.Code {
+	case KeyEnter:
+		m.pathFilter = *m.input
+		m.input = nil
+		m.clampSelection()
+
+	case KeyEscape, KeyCtrlC:
+		m.input = nil
+
+	case KeyBackspace:
+		if *m.input != "" {
+			runes := []rune(*m.input)
+			*m.input = string(runes[:len(runes)-1])
+		}
+
+	case KeyRune:
+		*m.input += string(key.Rune)
+	}
+}
+
+func (m *Model) move(delta int) {
+	m.selected += delta
+	m.clampSelection()
+
+	// Moving away from the newest entry stops following
+	m.follow = m.selected == len(m.filtered())-1 && delta > 0
+}
+
+func (m *Model) clampSelection() {
+	count := len(m.filtered())
+	if m.selected >= count {
+		m.selected = count - 1
+	}
+
+	if m.selected < 0 {
+		m.selected = 0
+	}
+}
+
+func nextStatusFilter(current string) string {
+	filters := []string{"", "2xx", "3xx", "4xx", "5xx", statusErrors}
+	for i, filter := range filters {
+		if filter == current {
+			return filters[(i+1)%len(filters)]
+		}
+	}
+
+	return ""
+}
+
+func (m *Model) filtered() []*Entry {
+	if m.statusFilter == "" && m.pathFilter == "" {
+		return m.entries
+	}
+
+	entries := make([]*Entry, 0, len(m.entries))
+	for _, entry := range m.entries {
+		if m.matches(entry.Exchange) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+func (m *Model) matches(exchange *tunnel.Exchange) bool {
+	if m.pathFilter != "" && !strings.Contains(exchange.Request.Path, m.pathFilter) {
+		return false
+	}
+
+	switch m.statusFilter {
+	case "":
+		return true
+
+	case statusErrors:
+		return exchange.Error != nil || exchange.TimedOut
+
+	default:
+		return exchange.Response != nil && stats.StatusClass(exchange.Response.Status) == m.statusFilter
+	}
+}
+
+// Render returns the screen lines for given terminal size
+func (m *Model) Render(ansi *ansi.Ansi, width int, height int) []string {
+	entries := m.filtered()
+
+	tableHeight := (height - 4) / 2
+	if tableHeight < 1 {
+		tableHeight = 1
+	}
+
+	if m.selected < m.offset {
+		m.offset = m.selected
+	}
+
+	if m.selected >= m.offset+tableHeight {
+		m.offset = m.selected - tableHeight + 1
+	}
+
+	lines := make([]string, 0, height)
+	lines = append(lines, ansi.Bold(truncate(m.title(len(entries)), width)))
+	lines = append(lines, ansi.Bold(truncate(fmt.Sprintf("%-8s  %-6s  %10s  %s", "TIME", "STATUS", "LATENCY", "PATH"), width)))
+
+	for i := m.offset; i < m.offset+tableHeight; i++ {
+		if i >= len(entries) {
+			lines = append(lines, "")
+
+			continue
+		}
+
+		row := truncate(formatRow(entries[i]), width)
+		if i == m.selected {
+			row = ansi.Reverse(pad(row, width))
+		} else if failed(entries[i].Exchange) {
+			row = ansi.Red(row)
+		}
+
+		lines = append(lines, row)
+	}
+
+	lines = append(lines, strings.Repeat("─", width))
+
+	detailHeight := height - len(lines) - 1
+	detail := m.detail()
+	for i := 0; i < detailHeight; i++ {
+		if i < len(detail) {
+			lines = append(lines, truncate(detail[i], width))
+		} else {
+			lines = append(lines, "")
+		}
+	}
+
+	lines = append(lines, truncate(m.statusLine(), width))
+
+	return lines
+}
+
+func (m *Model) title(shown int) string {
+	title := fmt.Sprintf("Corbado webhooks: %d of %d shown", shown, len(m.entries))
+
+	if m.statusFilter != "" {
+		title += ", status: " + m.statusFilter
+	}
+
+	if m.pathFilter != "" {
+		title += ", path: " + m.pathFilter
+	}
+
+	if m.follow {
+		title += " (following)"
+	}
+
+	return title
+}
+
+func (m *Model) statusLine() string {
+	if m.input != nil {
+		return "Path filter (enter to apply, esc to cancel): " + *m.input
+	}
+
+	if m.message != "" {
+		return m.message + "  |  " + helpLine
+	}
+
+	return helpLine
+}
+
+func formatRow(entry *Entry) string {
+	exchange := entry.Exchange
+
+	status := "-"
+	if exchange.TimedOut {
+		status = "TIMEOUT"
+	} else if exchange.Response != nil {
+		status = fmt.Sprintf("%d", exchange.Response.Status)
+	}
+
+	path := exchange.Request.Path
+	if entry.Replay {
+		path += " (replay)"
+	}
+
+	return fmt.Sprintf("%-8s  %-6s  %s  %s", exchange.Time.Format("15:04:05"), status, format.Latency(exchange.Latency), path)
+}
+
+func failed(exchange *tunnel.Exchange) bool {
+	return exchange.Error != nil || exchange.TimedOut || (exchange.Response != nil && exchange.Response.Status >= http.StatusBadRequest)
+}
+
+// detail returns the lines of the detail pane of the selected entry
+func (m *Model) detail() []string {
+	entry := m.Selected()
+	if entry == nil {
+		return []string{"No webhook requests yet"}
+	}
+
+	req := entry.Exchange.Request.Redacted(m.redactor)
+
+	lines := []string{fmt.Sprintf("Request %s POST %s (%s)", req.ID, req.Path, entry.Exchange.Time.Format("2006-01-02 15:04:05"))}
+	lines = append(lines, headersAndBody(req.Headers, req.Body)...)
+
+	if entry.Exchange.Error != nil {
+		lines = append(lines, "Error: "+entry.Exchange.Error.Error())
+	}
+
+	if entry.Exchange.Response != nil {
+		resp := entry.Exchange.Response.Redacted(m.redactor)
+
+		lines = append(lines, "", fmt.Sprintf("Response %d (attempts: %d, latency: %s)", resp.Status, entry.Exchange.Attempts, format.Duration(entry.Exchange.Latency)))
+		lines = append(lines, headersAndBody(resp.Headers, resp.Body)...)
+	}
+
+	return lines
+}
+
+func headersAndBody(headers map[string]string, body string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("  %s: %s", name, headers[name]))
+	}
+
+	if body == "" {
+		return append(lines, "  (empty body)")
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := json.Indent(buffer, []byte(body), "", "  "); err == nil {
+		body = buffer.String()
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		lines = append(lines, "  "+line)
+	}
+
+	return lines
+}
+
+// truncate cuts given (plain) line to given width
+func truncate(line string, width int) string {
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+
+	if width < 1 {
+		return ""
+	}
+
+	return string(runes[:width-1]) + "…"
+}
+
+func pad(line string, width int) string {
+	length := len([]rune(line))
+	if length >= width {
+		return line
+	}
+
+	return line + strings.Repeat(" ", width-length)
+}
@@ -0,0 +1,251 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	leaveAltScreen = "\x1b[?25h\x1b[?1049l"
+	cursorHome     = "\x1b[H"
+	clearLine      = "\x1b[K"
+	clearBelow     = "\x1b[J"
+
+	refreshInterval = 200 * time.Millisecond
+)
+
+// ReplayFunc forwards given webhook request to the local address again, the
+// exchange must not be passed to observers (e.g. tunnel.Tunnel.Replay)
+type ReplayFunc func(req *tunnel.WebhookRequest) (*tunnel.Exchange, error)
+
+// ExportFunc exports given exchange to a file, it returns the file name
+type ExportFunc func(exchange *tunnel.Exchange) (string, error)
+
+// TUI is a full-screen dashboard of all webhook requests, it gets fed as
+// tunnel observer
+type TUI struct {
+	ansi   *ansi.Ansi
+	in     *os.File
+	out    *os.File
+	replay ReplayFunc
+	export ExportFunc
+
+	lock  sync.Mutex
+	model *Model
+	dirty chan struct{}
+
+	statusLock   sync.Mutex
+	statusBuffer []byte
+}
+
+// New returns new TUI instance, it draws on stdout and reads keys from stdin
+func New(ansi *ansi.Ansi, redactor *redact.Redactor, replay ReplayFunc, export ExportFunc) *TUI {
+	return &TUI{
+		ansi:   ansi,
+		in:     os.Stdin,
+		out:    os.Stdout,
+		replay: replay,
+		export: export,
+		model:  NewModel(redactor),
+		dirty:  make(chan struct{}, 1),
+	}
+}
+
+// Supported returns an error if stdin or stdout is no terminal
+func Supported() error {
+	if !ansi.IsTerminal(os.Stdin) || !ansi.IsTerminal(os.Stdout) {
+		return errors.New("the dashboard needs an interactive terminal")
+	}
+
+	return nil
+}
+
+// Observe adds given exchange to the table (implements tunnel.Observer)
+func (t *TUI) Observe(exchange *tunnel.Exchange) {
+	t.lock.Lock()
+	t.model.Add(&Entry{Exchange: exchange})
+	t.lock.Unlock()
+
+	t.redraw()
+}
+
+// Write shows every complete line written to the TUI in the status line
+// (e.g. warnings which would be printed over the dashboard otherwise)
+func (t *TUI) Write(p []byte) (int, error) {
+	t.statusLock.Lock()
+	t.statusBuffer = append(t.statusBuffer, p...)
+
+	var lines []string
+	for {
+		i := bytes.IndexByte(t.statusBuffer, '\n')
+		if i < 0 {
+			break
+		}
+
+		if line := strings.TrimSpace(ansi.Strip(string(t.statusBuffer[:i]))); line != "" {
+			lines = append(lines, line)
+		}
+
+		t.statusBuffer = t.statusBuffer[i+1:]
+	}
+	t.statusLock.Unlock()
+
+	if len(lines) > 0 {
+		t.lock.Lock()
+		t.model.SetMessage(lines[len(lines)-1])
+		t.lock.Unlock()
+
+		t.redraw()
+	}
+
+	return len(p), nil
+}
+
+// Run shows the dashboard until the user quits or given context is done,
+// the terminal is restored afterwards
+func (t *TUI) Run(ctx context.Context) error {
+	state, err := term.MakeRaw(int(t.in.Fd()))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		_ = term.Restore(int(t.in.Fd()), state)
+	}()
+
+	t.write(enterAltScreen)
+	defer t.write(leaveAltScreen)
+
+	keys := make(chan Key, 16)
+	go t.readKeys(keys)
+
+	// Polling the size works everywhere (there is no SIGWINCH on Windows)
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		width, height, err := term.GetSize(int(t.out.Fd()))
+		if err != nil {
+			width, height = 80, 24
+		}
+
+		t.draw(width, height)
+
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case key := <-keys:
+			if t.handleKey(key) == ActionQuit {
+				return nil
+			}
+
+		case <-t.dirty:
+
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *TUI) handleKey(key Key) Action {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	action := t.model.HandleKey(key)
+
+	switch action {
+	case ActionReplay:
+		t.startReplay(t.model.Selected())
+
+	case ActionExport:
+		file, err := t.export(t.model.Selected().Exchange)
+		if err != nil {
+			t.model.SetMessage("Export failed: " + err.Error())
+		} else {
+			t.model.SetMessage("Exported to " + file)
+		}
+	}
+
+	return action
+}
+
+// startReplay forwards the request of given entry again in the background,
+// it shows up as new entry once answered (only in the dashboard, replays do
+// not count as webhook requests anywhere else)
+func (t *TUI) startReplay(entry *Entry) {
+	original := entry.Exchange.Request
+	req := &tunnel.WebhookRequest{
+		ID:      original.ID,
+		Headers: original.Headers,
+		Path:    original.Path,
+		Body:    original.Body,
+	}
+
+	t.model.SetMessage(fmt.Sprintf("Replaying %s ...", req.ID))
+
+	go func() {
+		exchange, err := t.replay(req)
+
+		t.lock.Lock()
+		if err != nil {
+			t.model.SetMessage(fmt.Sprintf("Replaying %s failed: %s", req.ID, err.Error()))
+		} else {
+			t.model.Add(&Entry{Exchange: exchange, Replay: true})
+			t.model.SetMessage(fmt.Sprintf("Replayed %s", req.ID))
+		}
+		t.lock.Unlock()
+
+		t.redraw()
+	}()
+}
+
+func (t *TUI) readKeys(keys chan<- Key) {
+	buffer := make([]byte, 256)
+	for {
+		n, err := t.in.Read(buffer)
+		if err != nil {
+			if err != io.EOF {
+				keys <- Key{Code: KeyCtrlC}
+			}
+
+			return
+		}
+
+		for _, key := range ParseKeys(buffer[:n]) {
+			keys <- key
+		}
+	}
+}
+
+func (t *TUI) redraw() {
+	select {
+	case t.dirty <- struct{}{}:
+	default:
+	}
+}
+
+func (t *TUI) draw(width int, height int) {
+	t.lock.Lock()
+	lines := t.model.Render(t.ansi, width, height)
+	t.lock.Unlock()
+
+	// Raw mode needs explicit carriage returns
+	t.write(cursorHome + strings.Join(lines, clearLine+"\r\n") + clearLine + clearBelow)
+}
+
+func (t *TUI) write(s string) {
+	_, _ = io.WriteString(t.out, s)
+}
@@ -0,0 +1,127 @@
+package tui_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/redact"
+	"github.com/corbado/cli/pkg/tui"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func entry(path string, status int) *tui.Entry {
+	return &tui.Entry{Exchange: &tunnel.Exchange{
+		Time:     time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC),
+		Request:  &tunnel.WebhookRequest{ID: "who-" + path, Path: path, Headers: map[string]string{"Authorization": "Basic secret"}, Body: `{"username":"jane","data":{"password":"secret"}}`},
+		Response: &tunnel.WebhookResponse{Status: status, Body: `{"status":"exists"}`},
+		Latency:  2 * time.Millisecond,
+	}}
+}
+
+func runeKey(r rune) tui.Key {
+	return tui.Key{Code: tui.KeyRune, Rune: r}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys := tui.ParseKeys([]byte("q\x1b[A\x1b[B\x1b[5~\r\x7f\x03\x1b"))
+
+	assert.Equal(t, []tui.Key{
+		{Code: tui.KeyRune, Rune: 'q'},
+		{Code: tui.KeyUp},
+		{Code: tui.KeyDown},
+		{Code: tui.KeyPageUp},
+		{Code: tui.KeyEnter},
+		{Code: tui.KeyBackspace},
+		{Code: tui.KeyCtrlC},
+		{Code: tui.KeyEscape},
+	}, keys)
+}
+
+func TestModelFollowsAndSelects(t *testing.T) {
+	model := tui.NewModel(redact.New(nil, nil))
+	assert.Nil(t, model.Selected())
+
+	model.Add(entry("/a", http.StatusOK))
+	model.Add(entry("/b", http.StatusOK))
+	assert.Equal(t, "/b", model.Selected().Exchange.Request.Path)
+
+	model.HandleKey(tui.Key{Code: tui.KeyUp})
+	assert.Equal(t, "/a", model.Selected().Exchange.Request.Path)
+
+	// Not following anymore
+	model.Add(entry("/c", http.StatusOK))
+	assert.Equal(t, "/a", model.Selected().Exchange.Request.Path)
+
+	model.HandleKey(runeKey('f'))
+	assert.Equal(t, "/c", model.Selected().Exchange.Request.Path)
+
+	assert.Equal(t, tui.ActionReplay, model.HandleKey(runeKey('r')))
+	assert.Equal(t, tui.ActionExport, model.HandleKey(runeKey('x')))
+	assert.Equal(t, tui.ActionQuit, model.HandleKey(runeKey('q')))
+	assert.Equal(t, tui.ActionQuit, model.HandleKey(tui.Key{Code: tui.KeyCtrlC}))
+}
+
+func TestModelFilters(t *testing.T) {
+	model := tui.NewModel(redact.New(nil, nil))
+	model.Add(entry("/users", http.StatusOK))
+	model.Add(entry("/users", http.StatusInternalServerError))
+	model.Add(entry("/sessions", http.StatusOK))
+
+	failed := entry("/users", http.StatusServiceUnavailable)
+	failed.Exchange.Error = errors.New("refused")
+	model.Add(failed)
+
+	// Status filter cycles through all, 2xx, 3xx, 4xx, 5xx and errors
+	model.HandleKey(runeKey('s'))
+	assert.Contains(t, render(model), "2 of 4 shown, status: 2xx")
+
+	model.HandleKey(runeKey('s'))
+	model.HandleKey(runeKey('s'))
+	model.HandleKey(runeKey('s'))
+	assert.Contains(t, render(model), "2 of 4 shown, status: 5xx")
+
+	model.HandleKey(runeKey('s'))
+	assert.Contains(t, render(model), "1 of 4 shown, status: errors")
+
+	model.HandleKey(runeKey('c'))
+	model.HandleKey(runeKey('/'))
+	for _, r := range "/sessx" {
+		model.HandleKey(runeKey(r))
+	}
+	model.HandleKey(tui.Key{Code: tui.KeyBackspace})
+	assert.Contains(t, render(model), "Path filter (enter to apply, esc to cancel): /sess")
+
+	model.HandleKey(tui.Key{Code: tui.KeyEnter})
+	assert.Contains(t, render(model), "1 of 4 shown, path: /sess")
+	assert.Equal(t, "/sessions", model.Selected().Exchange.Request.Path)
+}
+
+func TestModelRender(t *testing.T) {
+	model := tui.NewModel(redact.New(nil, []string{"username"}))
+	model.Add(entry("/webhook", http.StatusOK))
+
+	lines := model.Render(ansi.New(false, nil), 60, 30)
+	assert.Len(t, lines, 30)
+
+	screen := strings.Join(lines, "\n")
+	assert.Contains(t, screen, "15:04:05  200     ")
+	assert.Contains(t, screen, "Authorization: [REDACTED]")
+	assert.Contains(t, screen, `"username": "[REDACTED]"`)
+	assert.Contains(t, screen, `"password": "[REDACTED]"`)
+	assert.NotContains(t, screen, "secret")
+	assert.Contains(t, screen, `"status": "exists"`)
+
+	for _, line := range lines {
+		assert.LessOrEqual(t, len([]rune(line)), 60)
+	}
+}
+
+func render(model *tui.Model) string {
+	return strings.Join(model.Render(ansi.New(false, nil), 120, 30), "\n")
+}
@@ -0,0 +1,67 @@
+package tunnel
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	ConditionTimeout = "timeout"
+	ConditionError   = "error"
+)
+
+// ValidateCondition checks if given exchange condition is known (see MatchCondition)
+func ValidateCondition(condition string) error {
+	switch condition {
+	case LocalErrorRefused, LocalErrorDNS, LocalErrorReset, ConditionTimeout, ConditionError:
+		return nil
+	}
+
+	if isStatusClass(condition) {
+		return nil
+	}
+
+	if status, err := strconv.Atoi(condition); err == nil && status >= 100 && status <= 599 {
+		return nil
+	}
+
+	return errors.Errorf("'%s' (allowed: refused, dns, reset, timeout, error, status code like 503 or status class like 5xx)", condition)
+}
+
+// MatchCondition returns true if given exchange matches given condition which
+// is one of: a local error kind (refused, dns, reset), timeout, error (every
+// local error), an HTTP status code (e.g. 503) or an HTTP status class (e.g.
+// 5xx). Status codes and classes match the status sent back to Corbado, which
+// includes the status of local errors and timeouts (e.g. 503 if the local
+// address refused the connection).
+func MatchCondition(condition string, exchange *Exchange) bool {
+	var localErr *LocalError
+	isLocalErr := errors.As(exchange.Error, &localErr)
+
+	switch condition {
+	case ConditionTimeout:
+		return exchange.TimedOut
+
+	case ConditionError:
+		return isLocalErr
+
+	case LocalErrorRefused, LocalErrorDNS, LocalErrorReset:
+		return isLocalErr && localErr.Kind == condition
+	}
+
+	if exchange.Response == nil {
+		return false
+	}
+
+	if isStatusClass(condition) {
+		return strings.HasPrefix(strconv.Itoa(exchange.Response.Status), condition[:1])
+	}
+
+	return condition == strconv.Itoa(exchange.Response.Status)
+}
+
+func isStatusClass(condition string) bool {
+	return len(condition) == 3 && condition[0] >= '1' && condition[0] <= '5' && strings.EqualFold(condition[1:], "xx")
+}
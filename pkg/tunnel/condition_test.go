@@ -0,0 +1,51 @@
+package tunnel_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func TestMatchCondition(t *testing.T) {
+	answered := &tunnel.Exchange{
+		Response: &tunnel.WebhookResponse{Status: http.StatusInternalServerError},
+	}
+
+	refused := &tunnel.Exchange{
+		Response: &tunnel.WebhookResponse{Status: http.StatusServiceUnavailable},
+		Error:    &tunnel.LocalError{Kind: tunnel.LocalErrorRefused, Status: http.StatusServiceUnavailable},
+	}
+
+	timedOut := &tunnel.Exchange{
+		Response: &tunnel.WebhookResponse{Status: http.StatusGatewayTimeout},
+		TimedOut: true,
+	}
+
+	tests := []struct {
+		condition string
+		exchange  *tunnel.Exchange
+		match     bool
+	}{
+		{"5xx", answered, true},
+		{"500", answered, true},
+		{"4xx", answered, false},
+		{"error", answered, false},
+		{"5xx", refused, true},
+		{"503", refused, true},
+		{"refused", refused, true},
+		{"error", refused, true},
+		{"reset", refused, false},
+		{"5xx", timedOut, true},
+		{"504", timedOut, true},
+		{"timeout", timedOut, true},
+		{"timeout", answered, false},
+		{"5xx", &tunnel.Exchange{}, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.match, tunnel.MatchCondition(test.condition, test.exchange), "%s", test.condition)
+	}
+}
@@ -1,5 +1,9 @@
 package tunnel
 
+import (
+	"github.com/corbado/cli/pkg/redact"
+)
+
 type WebhookRequest struct {
 	ID      string            `json:"id"`
 	Headers map[string]string `json:"headers"`
@@ -13,3 +17,23 @@ type WebhookResponse struct {
 	Headers map[string]string `json:"headers"`
 	Body    string            `json:"body"`
 }
+
+// Redacted returns a copy of the webhook request with headers and body masked by given redactor
+func (w *WebhookRequest) Redacted(r *redact.Redactor) *WebhookRequest {
+	return &WebhookRequest{
+		ID:      w.ID,
+		Headers: r.Headers(w.Headers),
+		Path:    w.Path,
+		Body:    r.Body(w.Body),
+	}
+}
+
+// Redacted returns a copy of the webhook response with headers and body masked by given redactor
+func (w *WebhookResponse) Redacted(r *redact.Redactor) *WebhookResponse {
+	return &WebhookResponse{
+		ID:      w.ID,
+		Status:  w.Status,
+		Headers: r.Headers(w.Headers),
+		Body:    r.Body(w.Body),
+	}
+}
@@ -0,0 +1,3 @@
+package tunnel
+
+var ClassifyLocalError = classifyLocalError
@@ -0,0 +1,18 @@
+package tunnel
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrDropped is returned by a handler to send no response at all for a
+// webhook request (Corbado runs into its timeout)
+var ErrDropped = errors.New("Webhook request dropped")
+
+// Handler fills the response of given exchange, forward sends the webhook
+// request to the local address (e.g. to answer some requests locally and
+// forward all others)
+type Handler func(exchange *Exchange, forward func(exchange *Exchange) error) error
+
+// Validator checks the response of given exchange before it is sent back
+// (e.g. to warn about responses Corbado cannot process)
+type Validator func(exchange *Exchange)
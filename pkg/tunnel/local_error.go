@@ -0,0 +1,63 @@
+package tunnel
+
+import (
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	LocalErrorRefused = "refused"
+	LocalErrorDNS     = "dns"
+	LocalErrorReset   = "reset"
+	LocalErrorOther   = "other"
+)
+
+// LocalError is an error forwarding a webhook request to the local address
+type LocalError struct {
+	Kind    string
+	Status  int
+	Message string
+	Err     error
+}
+
+func (e *LocalError) Error() string {
+	return e.Message
+}
+
+func (e *LocalError) Unwrap() error {
+	return e.Err
+}
+
+// classifyLocalError converts given error of the HTTP client into a local error
+// with a fitting HTTP status and a short message which is safe to send through
+// the tunnel (no stack traces or internal details)
+func classifyLocalError(err error) *LocalError {
+	localErr := &LocalError{
+		Kind:    LocalErrorOther,
+		Status:  http.StatusBadGateway,
+		Message: "local address failed to answer",
+		Err:     err,
+	}
+
+	var dnsErr *net.DNSError
+
+	switch {
+	case isConnectionRefused(err):
+		localErr.Kind = LocalErrorRefused
+		localErr.Status = http.StatusServiceUnavailable
+		localErr.Message = "local address refused connection (is your application running?)"
+
+	case errors.As(err, &dnsErr):
+		localErr.Kind = LocalErrorDNS
+		localErr.Message = "local address could not be resolved"
+
+	case isConnectionReset(err), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		localErr.Kind = LocalErrorReset
+		localErr.Message = "local address closed connection unexpectedly"
+	}
+
+	return localErr
+}
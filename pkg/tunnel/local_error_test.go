@@ -0,0 +1,60 @@
+package tunnel_test
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// opError wraps given errno like the HTTP client does for network errors
+func opError(op string, errno error) error {
+	return &net.OpError{Op: op, Net: "tcp", Err: os.NewSyscallError(op, errno)}
+}
+
+func TestClassifyLocalError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		kind   string
+		status int
+	}{
+		{"refused", opError("connect", errConnectionRefused), tunnel.LocalErrorRefused, http.StatusServiceUnavailable},
+		{"reset", opError("read", errConnectionReset), tunnel.LocalErrorReset, http.StatusBadGateway},
+		{"eof", fmt.Errorf("read response: %w", io.EOF), tunnel.LocalErrorReset, http.StatusBadGateway},
+		{"unexpected eof", io.ErrUnexpectedEOF, tunnel.LocalErrorReset, http.StatusBadGateway},
+		{"dns", &net.DNSError{Err: "no such host", Name: "app.invalid"}, tunnel.LocalErrorDNS, http.StatusBadGateway},
+		{"other", fmt.Errorf("tls: handshake failure"), tunnel.LocalErrorOther, http.StatusBadGateway},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			localErr := tunnel.ClassifyLocalError(test.err)
+
+			assert.Equal(t, test.kind, localErr.Kind)
+			assert.Equal(t, test.status, localErr.Status)
+			assert.ErrorIs(t, localErr, test.err)
+		})
+	}
+}
+
+func TestClassifyLocalErrorStoppedServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	address := listener.Addr().String()
+	assert.NoError(t, listener.Close())
+
+	_, err = http.Get("http://" + address) //nolint:noctx,bodyclose
+	if !assert.Error(t, err) {
+		return
+	}
+
+	assert.Equal(t, tunnel.LocalErrorRefused, tunnel.ClassifyLocalError(err).Kind)
+}
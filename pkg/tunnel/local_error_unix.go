@@ -0,0 +1,17 @@
+//go:build !windows
+
+package tunnel
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+func isConnectionRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+func isConnectionReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET)
+}
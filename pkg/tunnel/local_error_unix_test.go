@@ -0,0 +1,12 @@
+//go:build !windows
+
+package tunnel_test
+
+import (
+	"syscall"
+)
+
+var (
+	errConnectionRefused = syscall.ECONNREFUSED
+	errConnectionReset   = syscall.ECONNRESET
+)
@@ -0,0 +1,24 @@
+//go:build windows
+
+package tunnel
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// Winsock error codes, the network stack on Windows never returns
+// syscall.ECONNREFUSED or syscall.ECONNRESET
+const (
+	wsaeconnreset   = syscall.Errno(10054)
+	wsaeconnrefused = syscall.Errno(10061)
+)
+
+func isConnectionRefused(err error) bool {
+	return errors.Is(err, wsaeconnrefused) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+func isConnectionReset(err error) bool {
+	return errors.Is(err, wsaeconnreset) || errors.Is(err, syscall.ECONNRESET)
+}
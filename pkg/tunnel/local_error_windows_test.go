@@ -0,0 +1,13 @@
+//go:build windows
+
+package tunnel_test
+
+import (
+	"syscall"
+)
+
+// Winsock error codes WSAECONNREFUSED and WSAECONNRESET
+var (
+	errConnectionRefused = syscall.Errno(10061)
+	errConnectionReset   = syscall.Errno(10054)
+)
@@ -0,0 +1,31 @@
+package tunnel
+
+import (
+	"time"
+)
+
+// Exchange is a webhook request received through the tunnel together with
+// the response that was sent back
+type Exchange struct {
+	Time     time.Time
+	Request  *WebhookRequest
+	Response *WebhookResponse
+
+	// Latency is the time the local address needed to answer (last attempt)
+	Latency  time.Duration
+	Attempts int
+	TimedOut bool
+	Error    error
+}
+
+type Observer interface {
+	Observe(exchange *Exchange)
+}
+
+// ObserverFunc is an adapter to allow the use of ordinary functions as observers
+type ObserverFunc func(exchange *Exchange)
+
+// Observe calls f(exchange)
+func (f ObserverFunc) Observe(exchange *Exchange) {
+	f(exchange)
+}
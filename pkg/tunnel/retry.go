@@ -0,0 +1,90 @@
+package tunnel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy defines if and how often a webhook request is re-sent to the
+// local address before the response is sent back through the tunnel
+type RetryPolicy struct {
+	// Attempts is the total number of attempts, 1 disables retries
+	Attempts int
+
+	// Backoff is the wait time before the first retry, it doubles after each retry
+	Backoff time.Duration
+
+	// Deadline is the total time all attempts together may take
+	Deadline time.Duration
+
+	// On lists the retryable conditions (see MatchCondition)
+	On []string
+}
+
+// DefaultRetryOn returns the conditions retried by default (typical for a restarting local application)
+func DefaultRetryOn() []string {
+	return []string{LocalErrorRefused, LocalErrorReset, "502", "503"}
+}
+
+// Validate checks the retry policy
+func (p *RetryPolicy) Validate() error {
+	if p.Attempts < 1 {
+		return errors.New("retry attempts must be at least 1")
+	}
+
+	if p.Backoff < 0 {
+		return errors.New("retry backoff must not be negative")
+	}
+
+	if p.Attempts > 1 && p.Deadline <= 0 {
+		return errors.New("retry deadline must be positive")
+	}
+
+	for _, condition := range p.On {
+		if err := ValidateCondition(condition); err != nil {
+			return errors.Errorf("unknown retry condition %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (p *RetryPolicy) enabled() bool {
+	return p != nil && p.Attempts > 1
+}
+
+// retryable returns the matching condition if given exchange should be retried
+func (p *RetryPolicy) retryable(exchange *Exchange) (string, bool) {
+	for _, condition := range p.On {
+		if MatchCondition(condition, exchange) {
+			return condition, true
+		}
+	}
+
+	return "", false
+}
+
+// backoff returns the wait time after given (1-based) attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	return p.Backoff * time.Duration(1<<(attempt-1))
+}
+
+func (t *Tunnel) printRetry(method string, url string, wait time.Duration, attempt int, condition string) {
+	if t.quiet {
+		return
+	}
+
+	fmt.Fprintf(
+		t.out,
+		"[%s] Retrying %s %s in %s (attempt %d/%d, retryable: %s)\n",
+		time.Now().Format("2006-01-02 15:04:05"),
+		t.ansi.Bold(method),
+		url,
+		wait,
+		attempt,
+		t.retryPolicy.Attempts,
+		condition,
+	)
+}
@@ -0,0 +1,29 @@
+package tunnel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func TestRetryPolicyValidate(t *testing.T) {
+	valid := &tunnel.RetryPolicy{
+		Attempts: 3,
+		Backoff:  time.Second,
+		Deadline: 10 * time.Second,
+		On:       []string{"refused", "dns", "reset", "timeout", "error", "503", "5xx"},
+	}
+	assert.NoError(t, valid.Validate())
+
+	disabled := &tunnel.RetryPolicy{Attempts: 1}
+	assert.NoError(t, disabled.Validate())
+
+	assert.Error(t, (&tunnel.RetryPolicy{Attempts: 0}).Validate())
+	assert.Error(t, (&tunnel.RetryPolicy{Attempts: 2, Backoff: -time.Second, Deadline: time.Second}).Validate())
+	assert.Error(t, (&tunnel.RetryPolicy{Attempts: 2}).Validate())
+	assert.Error(t, (&tunnel.RetryPolicy{Attempts: 2, Deadline: time.Second, On: []string{"600"}}).Validate())
+	assert.Error(t, (&tunnel.RetryPolicy{Attempts: 2, Deadline: time.Second, On: []string{"6xx"}}).Validate())
+}
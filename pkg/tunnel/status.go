@@ -0,0 +1,56 @@
+package tunnel
+
+import (
+	"time"
+)
+
+// Status describes the connection to the tunnel server
+type Status struct {
+	Connected       bool
+	ConnectedSince  time.Time
+	LastMessage     time.Time
+	Reconnects      int
+	WebsocketErrors int
+}
+
+// StatusSource returns the current status of the tunnel connection (e.g. Tunnel.Status)
+type StatusSource func() Status
+
+// Status returns a snapshot of the connection status
+func (t *Tunnel) Status() Status {
+	t.statusLock.RLock()
+	defer t.statusLock.RUnlock()
+
+	return t.status
+}
+
+func (t *Tunnel) setConnected(connected bool) {
+	t.statusLock.Lock()
+	defer t.statusLock.Unlock()
+
+	t.status.Connected = connected
+	if connected {
+		t.status.ConnectedSince = time.Now()
+	}
+}
+
+func (t *Tunnel) messageReceived() {
+	t.statusLock.Lock()
+	defer t.statusLock.Unlock()
+
+	t.status.LastMessage = time.Now()
+}
+
+func (t *Tunnel) websocketError() {
+	t.statusLock.Lock()
+	defer t.statusLock.Unlock()
+
+	t.status.WebsocketErrors++
+}
+
+func (t *Tunnel) reconnected() {
+	t.statusLock.Lock()
+	defer t.statusLock.Unlock()
+
+	t.status.Reconnects++
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/format"
 )
 
 var ErrUnauthorized = errors.New("Invalid credentials")
@@ -26,12 +27,36 @@ var ErrSessionExists = errors.New("Active session already exists")
 var ErrInternal = errors.New("Tunnel returned internal error. Please try again later")
 var ErrConnectionClosed = errors.New("Connection closed")
 
+// errResponseDropped is returned if a response could not be sent because the
+// tunnel is reconnecting, the tunnel server sends the webhook request again
+var errResponseDropped = errors.New("response dropped")
+
+const maxReconnectBackoff = 30 * time.Second
+
+// requestQueueSize is the number of webhook requests read ahead while the
+// current one is processed
+const requestQueueSize = 64
+
 type Tunnel struct {
 	ansi          *ansi.Ansi
 	tunnelAddress string
 	localAddress  string
+	projectID     string
+	cliSecret     string
+	reconnect     bool
 	conn          *websocket.Conn
 	httpClient    *http.Client
+	retryPolicy   *RetryPolicy
+	handler       Handler
+	validator     Validator
+	quiet         bool
+	out           io.Writer
+
+	statusLock sync.RWMutex
+	status     Status
+
+	observersLock sync.RWMutex
+	observers     []Observer
 
 	stopLock        sync.Mutex
 	shutdownContext context.Context
@@ -49,11 +74,56 @@ func New(ansi *ansi.Ansi, tunnelAddress string) *Tunnel {
 		ansi:            ansi,
 		tunnelAddress:   tunnelAddress,
 		httpClient:      httpClient,
+		out:             os.Stdout,
 		shutdownContext: shutdownContext,
 		cancel:          cancel,
 	}
 }
 
+// SetReconnect defines if the tunnel reconnects after losing the connection to the tunnel server
+func (t *Tunnel) SetReconnect(reconnect bool) {
+	t.reconnect = reconnect
+}
+
+// SetRetryPolicy sets the policy for re-sending webhook requests to the local address
+func (t *Tunnel) SetRetryPolicy(retryPolicy *RetryPolicy) {
+	t.retryPolicy = retryPolicy
+}
+
+// SetHandler sets the handler answering webhook requests instead of forwarding them to the local address
+func (t *Tunnel) SetHandler(handler Handler) {
+	t.handler = handler
+}
+
+// SetValidator sets the validator called with every response of the local address before it is sent back
+func (t *Tunnel) SetValidator(validator Validator) {
+	t.validator = validator
+}
+
+// SetQuiet defines if the line printed for every forwarded webhook request is suppressed (e.g. for load tests)
+func (t *Tunnel) SetQuiet(quiet bool) {
+	t.quiet = quiet
+}
+
+// SetOutput sets where the tunnel prints its messages to (os.Stdout by default)
+func (t *Tunnel) SetOutput(out io.Writer) {
+	t.out = out
+}
+
+// SetLocalAddress sets the local address webhook requests are forwarded to
+// (only needed for Forward, Start sets it as well)
+func (t *Tunnel) SetLocalAddress(localAddress string) {
+	t.localAddress = localAddress
+}
+
+// AddObserver adds an observer which gets notified about every handled webhook request
+func (t *Tunnel) AddObserver(observer Observer) {
+	t.observersLock.Lock()
+	defer t.observersLock.Unlock()
+
+	t.observers = append(t.observers, observer)
+}
+
 // Connect connects to tunnel server with given project ID and CLI secret
 func (t *Tunnel) Connect(projectID string, cliSecret string) error {
 	conn, resp, err := websocket.DefaultDialer.Dial(t.tunnelAddress, t.basicAuth(projectID, cliSecret)) //nolint:bodyclose
@@ -75,7 +145,13 @@ func (t *Tunnel) Connect(projectID string, cliSecret string) error {
 		return errors.WithStack(err)
 	}
 
+	t.stopLock.Lock()
 	t.conn = conn
+	t.projectID = projectID
+	t.cliSecret = cliSecret
+	t.stopLock.Unlock()
+
+	t.setConnected(true)
 
 	return nil
 }
@@ -92,30 +168,82 @@ func (t *Tunnel) Start(localAddress string) error {
 
 	t.localAddress = localAddress
 
+	// Webhook requests are processed one after another in the background so
+	// that reading (and with it answering pings and close messages of the
+	// tunnel server) goes on while a webhook request takes longer (e.g. in
+	// interactive mode)
+	requests := make(chan []byte, requestQueueSize)
+	processed := make(chan error, 1)
+	go func() {
+		processed <- t.processWebsocketRequests(requests)
+	}()
+
+	err := t.readWebsocketRequests(requests)
+	close(requests)
+
+	if errProcess := <-processed; errProcess != nil {
+		return errProcess
+	}
+
+	return err
+}
+
+// readWebsocketRequests reads webhook requests from the tunnel server into
+// given channel until the connection is closed or the tunnel is stopped
+func (t *Tunnel) readWebsocketRequests(requests chan<- []byte) error {
 	for {
 		select {
 		case <-t.shutdownContext.Done():
 			return nil
 
 		default:
-			_, req, err := t.conn.ReadMessage()
+			conn := t.connection()
+			if conn == nil {
+				return ErrConnectionClosed
+			}
+
+			_, req, err := conn.ReadMessage()
 			if err != nil {
-				if websocket.IsCloseError(err, 1000, 1006) {
-					return ErrConnectionClosed
+				if err := t.handleReadError(err); err != nil {
+					return err
 				}
 
-				if strings.Contains(err.Error(), "use of closed network connection") {
-					return ErrConnectionClosed
-				}
+				continue
+			}
+
+			t.messageReceived()
+
+			select {
+			case requests <- req:
+			case <-t.shutdownContext.Done():
+				return nil
+			}
+		}
+	}
+}
 
-				return errors.Errorf("error reading from tunnel server: %+v", err)
+// processWebsocketRequests processes webhook requests of given channel until
+// it is closed, the first error stops the tunnel
+func (t *Tunnel) processWebsocketRequests(requests <-chan []byte) error {
+	for req := range requests {
+		if t.shutdownContext.Err() != nil {
+			// Stopped, remaining webhook requests are not answered anymore
+			continue
+		}
+
+		if err := t.processWebsocketRequest(req); err != nil {
+			if errStop := t.Stop(); errStop != nil {
+				return errStop
 			}
 
-			if err := t.processWebsocketRequest(req); err != nil {
-				return err
+			for range requests {
 			}
+
+			return err
 		}
 	}
+
+	return nil
 }
 
 // Stop stops getting webhook requests from tunnel server
@@ -126,7 +254,10 @@ func (t *Tunnel) Stop() error {
 	t.cancel()
 
 	if t.conn != nil {
-		if err := t.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
+		// Close message was already sent if the tunnel server closed the connection,
+		// WriteControl is safe to call while a response is written
+		err := t.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		if err != nil && !errors.Is(err, websocket.ErrCloseSent) {
 			return errors.WithStack(err)
 		}
 
@@ -137,16 +268,98 @@ func (t *Tunnel) Stop() error {
 		t.conn = nil
 	}
 
+	t.setConnected(false)
+
 	return nil
 }
 
+func (t *Tunnel) connection() *websocket.Conn {
+	t.stopLock.Lock()
+	defer t.stopLock.Unlock()
+
+	return t.conn
+}
+
+// handleReadError returns nil if the tunnel reconnected and can continue reading
+func (t *Tunnel) handleReadError(err error) error {
+	t.setConnected(false)
+
+	if t.shutdownContext.Err() != nil {
+		return ErrConnectionClosed
+	}
+
+	if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		return ErrConnectionClosed
+	}
+
+	t.websocketError()
+
+	if t.reconnect {
+		return t.reconnectWithBackoff()
+	}
+
+	if websocket.IsCloseError(err, websocket.CloseAbnormalClosure) {
+		return ErrConnectionClosed
+	}
+
+	if strings.Contains(err.Error(), "use of closed network connection") {
+		return ErrConnectionClosed
+	}
+
+	return errors.Errorf("error reading from tunnel server: %+v", err)
+}
+
+func (t *Tunnel) reconnectWithBackoff() error {
+	t.stopLock.Lock()
+	if t.conn != nil {
+		_ = t.conn.Close()
+		t.conn = nil
+	}
+	t.stopLock.Unlock()
+
+	backoff := time.Second
+	for {
+		fmt.Fprintf(t.out, "[%s] Lost connection to tunnel server, reconnecting in %s ... ", time.Now().Format("2006-01-02 15:04:05"), backoff)
+
+		select {
+		case <-t.shutdownContext.Done():
+			fmt.Fprintln(t.out)
+
+			return ErrConnectionClosed
+
+		case <-time.After(backoff):
+		}
+
+		err := t.Connect(t.projectID, t.cliSecret)
+		if err == nil {
+			t.reconnected()
+			fmt.Fprintln(t.out, t.ansi.Bold(t.ansi.Green("success!")))
+
+			return nil
+		}
+
+		if err == ErrUnauthorized {
+			fmt.Fprintln(t.out, t.ansi.Bold(t.ansi.Red("failed (invalid credentials)!")))
+
+			return err
+		}
+
+		fmt.Fprintln(t.out, t.ansi.Bold(t.ansi.Red(fmt.Sprintf("failed (%s)!", err.Error()))))
+
+		backoff *= 2
+		if backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+}
+
 func (t *Tunnel) handleSignals() {
 	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
 
 	<-ch
 	if err := t.Stop(); err != nil {
-		fmt.Printf("Failed to gracefully stop the tunnel: %+v\n", err)
+		fmt.Fprintf(t.out, "Failed to gracefully stop the tunnel: %+v\n", err)
 	}
 }
 
@@ -164,28 +377,212 @@ func (t *Tunnel) processWebsocketRequest(req []byte) error {
 		return errors.Errorf("Received invalid payload from tunnel server: %s", string(req))
 	}
 
-	wresp, err := t.processWebhookRequest(wreq)
-	if err != nil {
-		if errResp := t.conn.WriteJSON(&WebhookResponse{
+	exchange := &Exchange{
+		Time:    time.Now(),
+		Request: wreq,
+	}
+
+	handle := t.processWebhookRequest
+	if t.handler != nil {
+		handle = func(exchange *Exchange) error {
+			return t.handler(exchange, t.processWebhookRequest)
+		}
+	}
+
+	if err := handle(exchange); err != nil {
+		if errors.Is(err, ErrDropped) {
+			return nil
+		}
+
+		exchange.Response = &WebhookResponse{
 			ID:     wreq.ID,
 			Status: http.StatusInternalServerError,
-			Body:   fmt.Sprintf("%+v", err),
-		}); errResp != nil {
-			return errors.WithStack(errResp)
+			Body:   err.Error(),
+		}
+		exchange.Error = err
+
+		if errResp := t.writeResponse(exchange.Response); errResp != nil {
+			if errors.Is(errResp, errResponseDropped) {
+				return nil
+			}
+
+			return errResp
+		}
+
+		t.notifyObservers(exchange)
+
+		return err
+	}
+
+	t.validate(exchange)
+
+	if err := t.writeResponse(exchange.Response); err != nil {
+		if errors.Is(err, errResponseDropped) {
+			return nil
 		}
 
 		return err
 	}
 
-	if err := t.conn.WriteJSON(wresp); err != nil {
+	t.notifyObservers(exchange)
+
+	return nil
+}
+
+// writeResponse sends given response through the tunnel, the connection is
+// gone if the tunnel was stopped or is reconnecting while the webhook request
+// was processed
+func (t *Tunnel) writeResponse(resp *WebhookResponse) error {
+	conn := t.connection()
+	if conn == nil {
+		if t.reconnecting() {
+			return t.dropResponse(resp)
+		}
+
+		return ErrConnectionClosed
+	}
+
+	if err := conn.WriteJSON(resp); err != nil {
+		if t.shutdownContext.Err() != nil {
+			return ErrConnectionClosed
+		}
+
+		t.websocketError()
+
+		if t.reconnecting() {
+			return t.dropResponse(resp)
+		}
+
 		return errors.WithStack(err)
 	}
 
 	return nil
 }
 
-func (t *Tunnel) processWebhookRequest(req *WebhookRequest) (*WebhookResponse, error) {
-	httpRequest := &http.Request{}
+// reconnecting returns true if the connection is gone but the tunnel was not
+// stopped and reconnects
+func (t *Tunnel) reconnecting() bool {
+	return t.reconnect && t.shutdownContext.Err() == nil
+}
+
+// dropResponse reports the response which could not be sent because of a lost
+// connection, the tunnel server sends the webhook request again after reconnecting
+func (t *Tunnel) dropResponse(resp *WebhookResponse) error {
+	fmt.Fprintf(t.out, "[%s] Lost connection to tunnel server, dropped response to webhook request %s (it will be sent again)\n", time.Now().Format("2006-01-02 15:04:05"), resp.ID)
+
+	return errResponseDropped
+}
+
+// Forward forwards given webhook request to the local address without the
+// tunnel server (e.g. for test suites), observers get notified as usual
+func (t *Tunnel) Forward(req *WebhookRequest) (*Exchange, error) {
+	exchange, err := t.Replay(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.notifyObservers(exchange)
+
+	return exchange, nil
+}
+
+// Replay forwards given webhook request to the local address again (e.g. from
+// the dashboard), observers are not notified since Corbado never sent it
+func (t *Tunnel) Replay(req *WebhookRequest) (*Exchange, error) {
+	exchange := &Exchange{
+		Time:    time.Now(),
+		Request: req,
+	}
+
+	if err := t.processWebhookRequest(exchange); err != nil {
+		return nil, err
+	}
+
+	t.validate(exchange)
+
+	return exchange, nil
+}
+
+// processTimeout answers a webhook request which timed out after given
+// duration with 504 and given message
+func (t *Tunnel) processTimeout(exchange *Exchange, httpRequest *http.Request, timeout time.Duration, message string) {
+	t.printMessage(
+		httpRequest.Method,
+		httpRequest.URL.String(),
+		len(exchange.Request.Body),
+		exchange.Latency,
+		timeout,
+		http.StatusGatewayTimeout,
+		0,
+	)
+
+	exchange.TimedOut = true
+	exchange.Response = &WebhookResponse{
+		ID:     exchange.Request.ID,
+		Status: http.StatusGatewayTimeout,
+		Body:   message,
+	}
+}
+
+func (t *Tunnel) validate(exchange *Exchange) {
+	if t.validator != nil && exchange.Response != nil {
+		t.validator(exchange)
+	}
+}
+
+func (t *Tunnel) processWebhookRequest(exchange *Exchange) error {
+	if !t.retryPolicy.enabled() {
+		exchange.Attempts = 1
+
+		return t.forwardWebhookRequest(context.Background(), exchange)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.retryPolicy.Deadline)
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+
+	for attempt := 1; ; attempt++ {
+		exchange.Attempts = attempt
+		exchange.Response = nil
+		exchange.Error = nil
+		exchange.TimedOut = false
+
+		if err := t.forwardWebhookRequest(ctx, exchange); err != nil {
+			return err
+		}
+
+		if attempt >= t.retryPolicy.Attempts {
+			return nil
+		}
+
+		condition, ok := t.retryPolicy.retryable(exchange)
+		if !ok {
+			return nil
+		}
+
+		wait := t.retryPolicy.backoff(attempt)
+		if time.Now().Add(wait).After(deadline) {
+			return nil
+		}
+
+		t.printRetry(http.MethodPost, t.localAddress+exchange.Request.Path, wait, attempt+1, condition)
+
+		select {
+		case <-t.shutdownContext.Done():
+			return nil
+
+		case <-time.After(wait):
+		}
+	}
+}
+
+// forwardWebhookRequest sends the webhook request once to the local address
+// and fills the exchange with the response (or the local error)
+func (t *Tunnel) forwardWebhookRequest(ctx context.Context, exchange *Exchange) error {
+	req := exchange.Request
+
+	httpRequest := (&http.Request{}).WithContext(ctx)
 	httpRequest.Method = http.MethodPost
 	httpRequest.Header = make(http.Header)
 	for name, value := range req.Headers {
@@ -194,60 +591,112 @@ func (t *Tunnel) processWebhookRequest(req *WebhookRequest) (*WebhookResponse, e
 
 	u, err := url.Parse(fmt.Sprintf("%s%s", t.localAddress, req.Path))
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return errors.WithStack(err)
 	}
 
 	httpRequest.URL = u
 	httpRequest.Body = io.NopCloser(strings.NewReader(req.Body))
 
+	start := time.Now()
+
 	httpResponse, err := t.httpClient.Do(httpRequest)
 	if err != nil {
+		exchange.Latency = time.Since(start)
+
+		// The retry deadline cancels the request as well, which looks like a
+		// timeout of the HTTP client otherwise
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			t.processTimeout(exchange, httpRequest, t.retryPolicy.Deadline, fmt.Sprintf(
+				"%s %s exceeded the retry deadline (%s) in attempt %d",
+				httpRequest.Method,
+				httpRequest.URL.String(),
+				t.retryPolicy.Deadline,
+				exchange.Attempts,
+			))
+
+			return nil
+		}
+
 		if os.IsTimeout(err) {
-			t.printMessage(
+			t.processTimeout(exchange, httpRequest, t.httpClient.Timeout, fmt.Sprintf(
+				"%s %s timed out (%s)",
 				httpRequest.Method,
 				httpRequest.URL.String(),
-				len(req.Body),
 				t.httpClient.Timeout,
-				http.StatusGatewayTimeout,
-				0,
-			)
-
-			return &WebhookResponse{
-				ID:     req.ID,
-				Status: http.StatusGatewayTimeout,
-				Body: fmt.Sprintf(
-					"%s %s timed out (%s)",
-					httpRequest.Method,
-					httpRequest.URL.String(),
-					t.httpClient.Timeout,
-				),
-			}, nil
+			))
+
+			return nil
 		}
 
-		return nil, errors.WithStack(err)
+		t.processLocalError(exchange, httpRequest, err)
+
+		return nil
 	}
 	defer httpResponse.Body.Close()
 
 	respBytes, err := io.ReadAll(httpResponse.Body)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		exchange.Latency = time.Since(start)
+		t.processLocalError(exchange, httpRequest, err)
+
+		return nil
 	}
 
+	exchange.Latency = time.Since(start)
+
 	t.printMessage(
 		httpRequest.Method,
 		httpRequest.URL.String(),
 		len(req.Body),
+		exchange.Latency,
 		0,
 		httpResponse.StatusCode,
 		len(respBytes),
 	)
 
-	return &WebhookResponse{
+	exchange.Response = &WebhookResponse{
 		ID:      req.ID,
 		Status:  httpResponse.StatusCode,
 		Headers: t.headersToMap(httpResponse.Header),
 		Body:    string(respBytes),
-	}, nil
+	}
+
+	return nil
+}
+
+// processLocalError answers the exchange with a sanitized error response, the
+// tunnel keeps running so that the next webhook request can be forwarded
+func (t *Tunnel) processLocalError(exchange *Exchange, httpRequest *http.Request, err error) {
+	localErr := classifyLocalError(err)
+
+	t.printFailure(
+		httpRequest.Method,
+		httpRequest.URL.String(),
+		len(exchange.Request.Body),
+		exchange.Latency,
+		localErr,
+	)
+
+	exchange.Error = localErr
+	exchange.Response = &WebhookResponse{
+		ID:     exchange.Request.ID,
+		Status: localErr.Status,
+		Body: fmt.Sprintf(
+			"%s %s failed: %s",
+			httpRequest.Method,
+			httpRequest.URL.String(),
+			localErr.Message,
+		),
+	}
+}
+
+func (t *Tunnel) notifyObservers(exchange *Exchange) {
+	t.observersLock.RLock()
+	defer t.observersLock.RUnlock()
+
+	for _, observer := range t.observers {
+		observer.Observe(exchange)
+	}
 }
 
 func (t *Tunnel) headersToMap(headers http.Header) map[string]string {
@@ -259,33 +708,55 @@ func (t *Tunnel) headersToMap(headers http.Header) map[string]string {
 	return result
 }
 
-func (t *Tunnel) printMessage(method string, url string, requestBodyLen int, timeout time.Duration, responseHTTPStatusCode int, responseBodyLen int) {
-	if responseHTTPStatusCode == http.StatusGatewayTimeout {
-		fmt.Printf(
-			"[%s] Corbado issued request > Received through tunnel > Local: %s %s (body: %s) > Timeout (%s) HTTP status %s (body: %s), sent it through tunnel > Corbado got response\n",
+func (t *Tunnel) printMessage(method string, url string, requestBodyLen int, latency time.Duration, timeout time.Duration, responseHTTPStatusCode int, responseBodyLen int) {
+	if t.quiet {
+		return
+	}
+
+	if timeout > 0 {
+		fmt.Fprintf(
+			t.out,
+			"[%s] [%s] Corbado issued request > Received through tunnel > Local: %s %s (body: %s) > Timeout (%s) HTTP status %s (body: %s), sent it through tunnel > Corbado got response\n",
 			time.Now().Format("2006-01-02 15:04:05"),
+			format.Latency(latency),
 			t.ansi.Bold(method),
 			url,
-			formatBytes(requestBodyLen),
-			timeout,
+			format.Bytes(requestBodyLen),
+			format.Duration(timeout),
 			t.ansi.ColorizeHTTPStatusCode(responseHTTPStatusCode),
-			formatBytes(responseBodyLen),
+			format.Bytes(responseBodyLen),
 		)
 
 		return
 	}
 
-	fmt.Printf(
-		"[%s] Corbado issued request > Received through tunnel > Local: %s %s (body: %s) > Got HTTP status %s (body: %s), sent it through tunnel > Corbado got response\n",
+	fmt.Fprintf(
+		t.out,
+		"[%s] [%s] Corbado issued request > Received through tunnel > Local: %s %s (body: %s) > Got HTTP status %s (body: %s), sent it through tunnel > Corbado got response\n",
 		time.Now().Format("2006-01-02 15:04:05"),
+		format.Latency(latency),
 		t.ansi.Bold(method),
 		url,
-		formatBytes(requestBodyLen),
+		format.Bytes(requestBodyLen),
 		t.ansi.ColorizeHTTPStatusCode(responseHTTPStatusCode),
-		formatBytes(responseBodyLen),
+		format.Bytes(responseBodyLen),
 	)
 }
 
-func formatBytes(bytes int) string {
-	return fmt.Sprintf("%.2f Kb", float64(bytes)/1024)
+func (t *Tunnel) printFailure(method string, url string, requestBodyLen int, latency time.Duration, localErr *LocalError) {
+	if t.quiet {
+		return
+	}
+
+	fmt.Fprintf(
+		t.out,
+		"[%s] [%s] Corbado issued request > Received through tunnel > Local: %s %s (body: %s) > Failed (%s) HTTP status %s, sent it through tunnel > Corbado got response\n",
+		time.Now().Format("2006-01-02 15:04:05"),
+		format.Latency(latency),
+		t.ansi.Bold(method),
+		url,
+		format.Bytes(requestBodyLen),
+		t.ansi.Red(localErr.Message),
+		t.ansi.ColorizeHTTPStatusCode(localErr.Status),
+	)
 }
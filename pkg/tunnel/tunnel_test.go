@@ -0,0 +1,71 @@
+package tunnel_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/ansi"
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+func TestForwardAndReplay(t *testing.T) {
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer localServer.Close()
+
+	observed := 0
+
+	tun := tunnel.New(ansi.New(false, nil), "")
+	tun.SetQuiet(true)
+	tun.SetLocalAddress(localServer.URL)
+	tun.AddObserver(tunnel.ObserverFunc(func(exchange *tunnel.Exchange) {
+		observed++
+	}))
+
+	exchange, err := tun.Forward(&tunnel.WebhookRequest{ID: "who-1", Path: "/webhook"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, exchange.Response.Status)
+	assert.Equal(t, 1, observed)
+
+	// Replays are no webhook requests of Corbado, so observers (statistics,
+	// history, limits etc.) must not see them
+	exchange, err = tun.Replay(&tunnel.WebhookRequest{ID: "who-1", Path: "/webhook"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, exchange.Response.Status)
+	assert.Equal(t, 1, observed)
+}
+
+func TestRetryDeadlineTimeout(t *testing.T) {
+	attempts := 0
+	localServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer localServer.Close()
+
+	tun := tunnel.New(ansi.New(false, nil), "")
+	tun.SetQuiet(true)
+	tun.SetLocalAddress(localServer.URL)
+	tun.SetRetryPolicy(&tunnel.RetryPolicy{Attempts: 3, Backoff: 10 * time.Millisecond, Deadline: 300 * time.Millisecond, On: []string{"503"}})
+
+	exchange, err := tun.Replay(&tunnel.WebhookRequest{ID: "who-1", Path: "/webhook"})
+	assert.NoError(t, err)
+	assert.True(t, exchange.TimedOut)
+	assert.Equal(t, 2, exchange.Attempts)
+	assert.Equal(t, http.StatusGatewayTimeout, exchange.Response.Status)
+	assert.Equal(t, "POST "+localServer.URL+"/webhook exceeded the retry deadline (300ms) in attempt 2", exchange.Response.Body)
+}
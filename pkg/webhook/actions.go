@@ -0,0 +1,71 @@
+package webhook
+
+const (
+	ActionAuthMethods    = "authMethods"
+	ActionPasswordVerify = "passwordVerify"
+)
+
+// AuthMethodsRequest is sent by Corbado to find out if a user exists in your system
+type AuthMethodsRequest struct {
+	*Request
+	Data AuthMethodsDataRequest `json:"data"`
+}
+
+type AuthMethodsDataRequest struct {
+	Username string `json:"username"`
+}
+
+// AuthMethodsResponse tells Corbado if the user exists in your system
+type AuthMethodsResponse struct {
+	ResponseID string                  `json:"responseID"`
+	Data       AuthMethodsDataResponse `json:"data"`
+}
+
+type AuthMethodsDataResponse struct {
+	Status AuthMethodsStatus `json:"status"`
+}
+
+type AuthMethodsStatus string
+
+const (
+	AuthMethodsStatusExists    AuthMethodsStatus = "exists"
+	AuthMethodsStatusNotExists AuthMethodsStatus = "not_exists"
+	AuthMethodsStatusBlocked   AuthMethodsStatus = "blocked"
+)
+
+// PasswordVerifyRequest is sent by Corbado to verify the password of an existing user
+type PasswordVerifyRequest struct {
+	*Request
+	Data PasswordVerifyDataRequest `json:"data"`
+}
+
+type PasswordVerifyDataRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// PasswordVerifyResponse tells Corbado if the password is correct
+type PasswordVerifyResponse struct {
+	ResponseID string                     `json:"responseID"`
+	Data       PasswordVerifyDataResponse `json:"data"`
+}
+
+type PasswordVerifyDataResponse struct {
+	Success bool `json:"success"`
+}
+
+// NewResponse returns the response with given status
+func (r *AuthMethodsRequest) NewResponse(status AuthMethodsStatus) *AuthMethodsResponse {
+	return &AuthMethodsResponse{
+		ResponseID: r.ID,
+		Data:       AuthMethodsDataResponse{Status: status},
+	}
+}
+
+// NewResponse returns the response with given result
+func (r *PasswordVerifyRequest) NewResponse(success bool) *PasswordVerifyResponse {
+	return &PasswordVerifyResponse{
+		ResponseID: r.ID,
+		Data:       PasswordVerifyDataResponse{Success: success},
+	}
+}
@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// BasicAuthHeader returns the Authorization header Corbado sends for the
+// webhook credentials configured in the developer panel
+func BasicAuthHeader(username string, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+// VerifyBasicAuthHeader returns true if given Authorization header contains
+// the expected credentials (compared in constant time)
+func VerifyBasicAuthHeader(header string, username string, password string) bool {
+	const prefix = "Basic "
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(header[len(prefix):])
+	if err != nil {
+		return false
+	}
+
+	givenUsername, givenPassword, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return false
+	}
+
+	// Both are compared so that the timing does not tell which one is wrong
+	usernameMatch := subtle.ConstantTimeCompare([]byte(givenUsername), []byte(username))
+	passwordMatch := subtle.ConstantTimeCompare([]byte(givenPassword), []byte(password))
+
+	return usernameMatch&passwordMatch == 1
+}
+
+// VerifyBasicAuth returns true if given HTTP request has the expected credentials
+func VerifyBasicAuth(r *http.Request, username string, password string) bool {
+	return VerifyBasicAuthHeader(r.Header.Get("Authorization"), username, password)
+}
+
+// VerifyWebhookRequest returns true if given webhook request received through
+// the tunnel has the expected credentials
+func VerifyWebhookRequest(req *tunnel.WebhookRequest, username string, password string) bool {
+	for name, value := range req.Headers {
+		if strings.EqualFold(name, "Authorization") {
+			return VerifyBasicAuthHeader(value, username, password)
+		}
+	}
+
+	return false
+}
+
+// BasicAuth returns a handler which rejects webhook requests without the
+// expected credentials and passes all others to next
+func BasicAuth(username string, password string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !VerifyBasicAuth(r, username, password) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="Corbado webhooks"`)
+			http.Error(w, "Invalid webhook credentials", http.StatusUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
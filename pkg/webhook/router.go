@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Router is an http.Handler dispatching Corbado webhook requests to the
+// handler registered for their action
+type Router struct {
+	handlers map[string]http.Handler
+}
+
+// NewRouter returns new router
+func NewRouter() *Router {
+	return &Router{
+		handlers: map[string]http.Handler{},
+	}
+}
+
+// Handle registers the handler for given action, it can read the body as usual
+func (r *Router) Handle(action string, handler http.Handler) {
+	r.handlers[action] = handler
+}
+
+// HandleAuthMethods registers a typed handler for authMethods webhook requests
+func (r *Router) HandleAuthMethods(handler func(ctx context.Context, req *AuthMethodsRequest) (AuthMethodsStatus, error)) {
+	r.Handle(ActionAuthMethods, typedHandler(func(ctx context.Context, req *Request) (any, error) {
+		typed, err := req.AuthMethods()
+		if err != nil {
+			return nil, &invalidDataError{err: err}
+		}
+
+		status, err := handler(ctx, typed)
+		if err != nil {
+			return nil, err
+		}
+
+		return typed.NewResponse(status), nil
+	}))
+}
+
+// HandlePasswordVerify registers a typed handler for passwordVerify webhook requests
+func (r *Router) HandlePasswordVerify(handler func(ctx context.Context, req *PasswordVerifyRequest) (bool, error)) {
+	r.Handle(ActionPasswordVerify, typedHandler(func(ctx context.Context, req *Request) (any, error) {
+		typed, err := req.PasswordVerify()
+		if err != nil {
+			return nil, &invalidDataError{err: err}
+		}
+
+		success, err := handler(ctx, typed)
+		if err != nil {
+			return nil, err
+		}
+
+		return typed.NewResponse(success), nil
+	}))
+}
+
+// ServeHTTP implements http.Handler
+func (r *Router) ServeHTTP(w http.ResponseWriter, httpRequest *http.Request) {
+	if httpRequest.Method != http.MethodPost {
+		http.Error(w, "Webhook requests must be sent with POST", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	req, err := FromHTTPRequest(httpRequest)
+	if err != nil {
+		http.Error(w, "Invalid webhook request: "+err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	handler, ok := r.handlers[req.Action]
+	if !ok {
+		http.Error(w, "Unknown webhook action "+req.Action, http.StatusBadRequest)
+
+		return
+	}
+
+	handler.ServeHTTP(w, httpRequest)
+}
+
+func typedHandler(handle func(ctx context.Context, req *Request) (any, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, httpRequest *http.Request) {
+		req, err := FromHTTPRequest(httpRequest)
+		if err != nil {
+			http.Error(w, "Invalid webhook request: "+err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		response, err := handle(httpRequest.Context(), req)
+		if err != nil {
+			var invalidData *invalidDataError
+			if errors.As(err, &invalidData) {
+				http.Error(w, "Invalid webhook request: "+invalidData.Error(), http.StatusBadRequest)
+
+				return
+			}
+
+			// Errors of the handler are internal, Corbado only needs to know that it failed
+			http.Error(w, "Webhook request could not be handled", http.StatusInternalServerError)
+
+			return
+		}
+
+		_ = WriteResponse(w, response)
+	})
+}
+
+// invalidDataError is returned if the data of a webhook request does not
+// match its action, it is answered with 400 instead of 500
+type invalidDataError struct {
+	err error
+}
+
+func (e *invalidDataError) Error() string {
+	return e.err.Error()
+}
@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/corbado/cli/pkg/tunnel"
+)
+
+// maxBodySize limits the body read from HTTP requests (webhook bodies are small)
+const maxBodySize = 1 << 20
+
+// Request is the envelope of every Corbado webhook request, Data depends on
+// Action (see AuthMethods and PasswordVerify)
+type Request struct {
+	ID        string          `json:"id"`
+	ProjectID string          `json:"projectID"`
+	Action    string          `json:"action"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Response is the envelope of every response to a Corbado webhook request,
+// ResponseID must be the ID of the webhook request
+type Response struct {
+	ResponseID string `json:"responseID"`
+	Data       any    `json:"data"`
+}
+
+// Parse decodes a webhook request body
+func Parse(body []byte) (*Request, error) {
+	req := &Request{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if req.Action == "" {
+		return nil, errors.New("webhook request has no action")
+	}
+
+	return req, nil
+}
+
+// FromWebhookRequest decodes the body of a webhook request received through the tunnel
+func FromWebhookRequest(req *tunnel.WebhookRequest) (*Request, error) {
+	return Parse([]byte(req.Body))
+}
+
+// FromHTTPRequest decodes the body of an HTTP request, the body can be read
+// again afterwards
+func FromHTTPRequest(r *http.Request) (*Request, error) {
+	if r.Body == nil {
+		return nil, errors.New("webhook request has no body")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return Parse(body)
+}
+
+// NewResponse returns the response to given webhook request with given data
+func (r *Request) NewResponse(data any) *Response {
+	return &Response{
+		ResponseID: r.ID,
+		Data:       data,
+	}
+}
+
+// AuthMethods returns the typed webhook request, it fails for other actions
+func (r *Request) AuthMethods() (*AuthMethodsRequest, error) {
+	data := AuthMethodsDataRequest{}
+	if err := r.decodeData(ActionAuthMethods, &data); err != nil {
+		return nil, err
+	}
+
+	return &AuthMethodsRequest{Request: r, Data: data}, nil
+}
+
+// PasswordVerify returns the typed webhook request, it fails for other actions
+func (r *Request) PasswordVerify() (*PasswordVerifyRequest, error) {
+	data := PasswordVerifyDataRequest{}
+	if err := r.decodeData(ActionPasswordVerify, &data); err != nil {
+		return nil, err
+	}
+
+	return &PasswordVerifyRequest{Request: r, Data: data}, nil
+}
+
+// Validate fails if the data does not match the typed request of the action,
+// the data of unknown actions is not checked
+func (r *Request) Validate() error {
+	switch r.Action {
+	case ActionAuthMethods:
+		_, err := r.AuthMethods()
+
+		return err
+
+	case ActionPasswordVerify:
+		_, err := r.PasswordVerify()
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *Request) decodeData(action string, data any) error {
+	if r.Action != action {
+		return errors.Errorf("webhook request has action %s, expected %s", r.Action, action)
+	}
+
+	// Missing data decodes like missing fields do, to their zero values
+	if len(r.Data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(r.Data, data); err != nil {
+		return errors.Errorf("invalid data of %s webhook request: %s", action, err.Error())
+	}
+
+	return nil
+}
+
+// WriteResponse writes given response (e.g. *Response or *AuthMethodsResponse) as JSON
+func WriteResponse(w http.ResponseWriter, response any) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(body)
+
+	return errors.WithStack(err)
+}
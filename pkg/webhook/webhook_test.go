@@ -0,0 +1,174 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/corbado/cli/pkg/jsonpath"
+	"github.com/corbado/cli/pkg/schema"
+	"github.com/corbado/cli/pkg/tunnel"
+	"github.com/corbado/cli/pkg/webhook"
+)
+
+const authMethodsBody = `{"id":"who-1","projectID":"pro-1","action":"authMethods","data":{"username":"jane@example.com"}}`
+
+const passwordVerifyBody = `{"id":"who-2","projectID":"pro-1","action":"passwordVerify","data":{"username":"jane@example.com","password":"secret"}}`
+
+func TestFromWebhookRequest(t *testing.T) {
+	req, err := webhook.FromWebhookRequest(&tunnel.WebhookRequest{ID: "req-1", Path: "/webhook", Body: authMethodsBody})
+	assert.NoError(t, err)
+	assert.Equal(t, "who-1", req.ID)
+	assert.Equal(t, "pro-1", req.ProjectID)
+
+	authMethods, err := req.AuthMethods()
+	assert.NoError(t, err)
+	assert.Equal(t, "jane@example.com", authMethods.Data.Username)
+	assert.Equal(t, "who-1", authMethods.ID)
+
+	_, err = req.PasswordVerify()
+	assert.EqualError(t, err, "webhook request has action authMethods, expected passwordVerify")
+
+	_, err = webhook.Parse([]byte(`{"id":"who-1"}`))
+	assert.EqualError(t, err, "webhook request has no action")
+}
+
+func TestValidate(t *testing.T) {
+	for body, valid := range map[string]bool{
+		authMethodsBody:    true,
+		passwordVerifyBody: true,
+		`{"action":"authMethods","data":{"username":1}}`:       false,
+		`{"action":"passwordVerify","data":{"password":true}}`: false,
+		`{"action":"custom","data":{"username":1}}`:            true,
+	} {
+		req, err := webhook.Parse([]byte(body))
+		assert.NoError(t, err)
+		assert.Equal(t, valid, req.Validate() == nil, body)
+	}
+}
+
+func TestFromHTTPRequestKeepsBody(t *testing.T) {
+	httpRequest := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(passwordVerifyBody))
+
+	req, err := webhook.FromHTTPRequest(httpRequest)
+	assert.NoError(t, err)
+
+	passwordVerify, err := req.PasswordVerify()
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", passwordVerify.Data.Password)
+
+	body, err := io.ReadAll(httpRequest.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, passwordVerifyBody, string(body))
+}
+
+// TestResponsesMatchCatalog makes sure the typed responses are what Corbado
+// expects according to the embedded schemas
+func TestResponsesMatchCatalog(t *testing.T) {
+	req, err := webhook.Parse([]byte(authMethodsBody))
+	assert.NoError(t, err)
+
+	authMethods, err := req.AuthMethods()
+	assert.NoError(t, err)
+
+	req, err = webhook.Parse([]byte(passwordVerifyBody))
+	assert.NoError(t, err)
+
+	passwordVerify, err := req.PasswordVerify()
+	assert.NoError(t, err)
+
+	responses := map[string]any{
+		webhook.ActionAuthMethods:    authMethods.NewResponse(webhook.AuthMethodsStatusBlocked),
+		webhook.ActionPasswordVerify: passwordVerify.NewResponse(false),
+	}
+
+	for name, response := range responses {
+		action, ok := schema.LookupAction(name)
+		assert.True(t, ok, name)
+
+		encoded, err := json.Marshal(response)
+		assert.NoError(t, err)
+
+		data, err := jsonpath.Parse(string(encoded))
+		assert.NoError(t, err)
+		assert.Empty(t, schema.Validate(action.Response, data), name)
+	}
+}
+
+func TestRouter(t *testing.T) {
+	router := webhook.NewRouter()
+	router.HandleAuthMethods(func(ctx context.Context, req *webhook.AuthMethodsRequest) (webhook.AuthMethodsStatus, error) {
+		if req.Data.Username == "jane@example.com" {
+			return webhook.AuthMethodsStatusExists, nil
+		}
+
+		return webhook.AuthMethodsStatusNotExists, nil
+	})
+	router.HandlePasswordVerify(func(ctx context.Context, req *webhook.PasswordVerifyRequest) (bool, error) {
+		return false, errors.New("database unavailable")
+	})
+	router.Handle("custom", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+
+	serve := func(method string, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, "/webhook", strings.NewReader(body)))
+
+		return rec
+	}
+
+	rec := serve(http.MethodPost, authMethodsBody)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"responseID":"who-1","data":{"status":"exists"}}`, rec.Body.String())
+
+	rec = serve(http.MethodPost, passwordVerifyBody)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "Webhook request could not be handled\n", rec.Body.String())
+
+	rec = serve(http.MethodPost, `{"id":"who-1","action":"authMethods","data":{"username":1}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Invalid webhook request: invalid data of authMethods webhook request")
+
+	rec = serve(http.MethodPost, `{"action":"custom"}`)
+	assert.Equal(t, `{"action":"custom"}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, `{"action":"unknown"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, `no json`).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodGet, "").Code)
+}
+
+func TestBasicAuth(t *testing.T) {
+	header := webhook.BasicAuthHeader("webhook", "secret")
+	assert.True(t, webhook.VerifyBasicAuthHeader(header, "webhook", "secret"))
+	assert.False(t, webhook.VerifyBasicAuthHeader(header, "webhook", "other"))
+	assert.False(t, webhook.VerifyBasicAuthHeader("Bearer token", "webhook", "secret"))
+	assert.False(t, webhook.VerifyBasicAuthHeader("Basic !", "webhook", "secret"))
+
+	assert.True(t, webhook.VerifyWebhookRequest(&tunnel.WebhookRequest{Headers: map[string]string{"authorization": header}}, "webhook", "secret"))
+	assert.False(t, webhook.VerifyWebhookRequest(&tunnel.WebhookRequest{}, "webhook", "secret"))
+
+	handler := webhook.BasicAuth("webhook", "secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", nil)
+	req.Header.Set("Authorization", header)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}